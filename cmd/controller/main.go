@@ -39,6 +39,9 @@ func main() {
 		op.GetClient(),
 		op.AMIProvider,
 		op.SecurityGroupProvider,
+		op.CarbonProvider,
+		op.RegionProvider,
+		op.Clock,
 	)
 	lo.Must0(op.AddHealthzCheck("cloud-provider", awsCloudProvider.LivenessProbe))
 	cloudProvider := metrics.Decorate(awsCloudProvider)
@@ -65,9 +68,13 @@ func main() {
 			op.InstanceProfileProvider,
 			op.InstanceProvider,
 			op.PricingProvider,
+			op.CarbonProvider,
 			op.AMIProvider,
 			op.LaunchTemplateProvider,
 			op.InstanceTypesProvider,
+			op.RegionProvider,
+			op.CommitmentProvider,
+			op.PricingOverrides,
 		)...).
 		WithWebhooks(ctx, webhooks.NewWebhooks()...).
 		Start(ctx)
@@ -0,0 +1,105 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+const fileFormat = `
+%s
+package carbon
+
+// GENERATED FILE. DO NOT EDIT DIRECTLY.
+// Update hack/code/carbon_gen/grid_intensity.csv and re-generate to edit
+
+var (
+	// InitialRegionIntensity is the average grid intensity of each region in gCO2e/kWh
+	InitialRegionIntensity = map[string]float64{
+		%s
+	}
+	// RegionPUE is the power usage effectiveness of the data centers in each region
+	RegionPUE = map[string]float64{
+		%s
+	}
+)
+`
+
+type Options struct {
+	input  string
+	output string
+}
+
+func NewOptions() *Options {
+	o := &Options{}
+	flag.StringVar(&o.input, "input", "hack/code/carbon_gen/grid_intensity.csv", "The CSV file containing the region, intensity and PUE of each region.")
+	flag.StringVar(&o.output, "output", "pkg/providers/carbon/zz_generated.carbon.go", "The destination for the generated go file.")
+	flag.Parse()
+	return o
+}
+
+func main() {
+	opts := NewOptions()
+
+	file := lo.Must(os.Open(opts.input))
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	records := lo.Must(reader.ReadAll())
+	file.Close()
+	if len(records) == 0 || strings.Join(records[0], ",") != "region,intensity_gco2e_per_kwh,pue" {
+		log.Fatalf("expected header \"region,intensity_gco2e_per_kwh,pue\" in %s", opts.input)
+	}
+
+	intensity := map[string]float64{}
+	pue := map[string]float64{}
+	for _, record := range records[1:] {
+		region := strings.TrimSpace(record[0])
+		if _, ok := intensity[region]; ok {
+			log.Fatalf("duplicate region %q in %s", region, opts.input)
+		}
+		intensity[region] = lo.Must(strconv.ParseFloat(strings.TrimSpace(record[1]), 64))
+		pue[region] = lo.Must(strconv.ParseFloat(strings.TrimSpace(record[2]), 64))
+		if intensity[region] < 0 || pue[region] < 1 {
+			log.Fatalf("invalid intensity or PUE for region %q in %s", region, opts.input)
+		}
+	}
+	regions := lo.Keys(intensity)
+	sort.Strings(regions)
+
+	// Generate body
+	var intensityBody, pueBody string
+	for _, region := range regions {
+		intensityBody += fmt.Sprintf("\t\"%s\": %f,\n", region, intensity[region])
+		pueBody += fmt.Sprintf("\t\"%s\": %f,\n", region, pue[region])
+	}
+
+	license := lo.Must(os.ReadFile("hack/boilerplate.go.txt"))
+
+	// Format and print to the file
+	formatted := lo.Must(format.Source([]byte(fmt.Sprintf(fileFormat, license, intensityBody, pueBody))))
+	out := lo.Must(os.Create(opts.output))
+	lo.Must(out.Write(formatted))
+	out.Close()
+}
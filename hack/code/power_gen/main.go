@@ -0,0 +1,139 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+const fileFormat = `
+%s
+package instancetype
+
+// GENERATED FILE. DO NOT EDIT DIRECTLY.
+// Update the CSV files in hack/code/power_gen and re-generate to edit
+
+var (
+	// MicroarchitectureCPUWatts is the idle and max draw of a single vCPU for each CPU microarchitecture
+	MicroarchitectureCPUWatts = map[string]Power{
+		%s
+	}
+	// InstanceFamilyCPUWatts is the idle and max draw of a single vCPU for each instance family
+	InstanceFamilyCPUWatts = map[string]Power{
+		%s
+	}
+	// AcceleratorWatts is the idle and max draw of a single GPU or accelerator device, keyed by device name
+	AcceleratorWatts = map[string]Power{
+		%s
+	}
+)
+`
+
+type Options struct {
+	input  string
+	output string
+}
+
+func NewOptions() *Options {
+	o := &Options{}
+	flag.StringVar(&o.input, "input", "hack/code/power_gen", "The directory containing microarchitectures.csv, families.csv and accelerators.csv.")
+	flag.StringVar(&o.output, "output", "pkg/providers/instancetype/zz_generated.power.go", "The destination for the generated go file.")
+	flag.Parse()
+	return o
+}
+
+func main() {
+	opts := NewOptions()
+
+	microarchitectures := map[string][2]float64{}
+	for _, record := range readCSV(filepath.Join(opts.input, "microarchitectures.csv"), "microarchitecture,min_watts_per_vcpu,max_watts_per_vcpu") {
+		microarchitectures[record[0]] = parseWatts(record)
+	}
+	families := map[string]string{}
+	for _, record := range readCSV(filepath.Join(opts.input, "families.csv"), "family,microarchitecture") {
+		if _, ok := microarchitectures[record[1]]; !ok {
+			log.Fatalf("family %q references unknown microarchitecture %q", record[0], record[1])
+		}
+		families[record[0]] = record[1]
+	}
+	accelerators := map[string][2]float64{}
+	for _, record := range readCSV(filepath.Join(opts.input, "accelerators.csv"), "accelerator,idle_watts,max_watts") {
+		accelerators[record[0]] = parseWatts(record)
+	}
+
+	// Generate body
+	var microarchitectureBody, familyBody, acceleratorBody string
+	for _, microarchitecture := range sortedKeys(microarchitectures) {
+		microarchitectureBody += fmt.Sprintf("\t%q: {IdleWatts: %g, MaxWatts: %g},\n", microarchitecture,
+			microarchitectures[microarchitecture][0], microarchitectures[microarchitecture][1])
+	}
+	for _, family := range sortedKeys(families) {
+		watts := microarchitectures[families[family]]
+		familyBody += fmt.Sprintf("\t%q: {IdleWatts: %g, MaxWatts: %g}, // %s\n", family, watts[0], watts[1], families[family])
+	}
+	for _, accelerator := range sortedKeys(accelerators) {
+		acceleratorBody += fmt.Sprintf("\t%q: {IdleWatts: %g, MaxWatts: %g},\n", accelerator,
+			accelerators[accelerator][0], accelerators[accelerator][1])
+	}
+
+	license := lo.Must(os.ReadFile("hack/boilerplate.go.txt"))
+
+	// Format and print to the file
+	formatted := lo.Must(format.Source([]byte(fmt.Sprintf(fileFormat, license, microarchitectureBody, familyBody, acceleratorBody))))
+	out := lo.Must(os.Create(opts.output))
+	lo.Must(out.Write(formatted))
+	out.Close()
+}
+
+// readCSV reads a commented CSV file, validating its header and returning the trimmed records that follow it
+func readCSV(path string, header string) [][]string {
+	file := lo.Must(os.Open(path))
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	records := lo.Must(reader.ReadAll())
+	if len(records) == 0 || strings.Join(records[0], ",") != header {
+		log.Fatalf("expected header %q in %s", header, path)
+	}
+	return lo.Map(records[1:], func(record []string, _ int) []string {
+		return lo.Map(record, func(field string, _ int) string { return strings.TrimSpace(field) })
+	})
+}
+
+func parseWatts(record []string) [2]float64 {
+	idle := lo.Must(strconv.ParseFloat(record[1], 64))
+	max := lo.Must(strconv.ParseFloat(record[2], 64))
+	if idle < 0 || max < idle {
+		log.Fatalf("invalid watts for %q, idle must be positive and no greater than max", record[0])
+	}
+	return [2]float64{idle, max}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := lo.Keys(m)
+	sort.Strings(keys)
+	return keys
+}
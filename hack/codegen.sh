@@ -32,6 +32,22 @@ pricing() {
   done
 }
 
+power() {
+  GENERATED_FILE="pkg/providers/instancetype/zz_generated.power.go"
+
+  go run hack/code/power_gen/main.go --input hack/code/power_gen --output "${GENERATED_FILE}"
+
+  checkForUpdates "${GENERATED_FILE}"
+}
+
+carbon() {
+  GENERATED_FILE="pkg/providers/carbon/zz_generated.carbon.go"
+
+  go run hack/code/carbon_gen/main.go --input hack/code/carbon_gen/grid_intensity.csv --output "${GENERATED_FILE}"
+
+  checkForUpdates "${GENERATED_FILE}"
+}
+
 vpcLimits() {
   GENERATED_FILE="pkg/providers/instancetype/zz_generated.vpclimits.go"
 
@@ -105,6 +121,10 @@ echo "Updating bandwidth..."
 bandwidth
 echo "Updating pricing..."
 pricing
+echo "Updating power coefficients..."
+power
+echo "Updating carbon intensity..."
+carbon
 echo "Updating VPC limits..."
 vpcLimits
 echo "Updating instance type data..."
@@ -117,6 +117,49 @@ type EC2NodeClassSpec struct {
 	// https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateFleet.html
 	// +optional
 	Context *string `json:"context,omitempty"`
+	// CarbonPolicy configures how the estimated carbon emissions of offerings are weighed when launching nodes.
+	// Changes to the carbon policy don't drift existing nodes unless driftOnChange is set.
+	// +optional
+	CarbonPolicy *CarbonPolicy `json:"carbonPolicy,omitempty" hash:"ignore"`
+	// AdditionalRegions are regions, other than the cluster's region, that nodes may be launched into. Offerings span
+	// the cluster's region and these regions, so that nodes are launched into whichever region has the cheapest offering,
+	// including the cost of carbon when the carbon policy prices it. Nodes in additional regions must be able to reach
+	// the cluster, such as through VPC peering.
+	// +kubebuilder:validation:XValidation:message="additional regions must be unique",rule="self.all(x, self.exists_one(y, x.name == y.name))"
+	// +kubebuilder:validation:MaxItems:=10
+	// +optional
+	AdditionalRegions []AdditionalRegion `json:"additionalRegions,omitempty" hash:"ignore"`
+}
+
+// AdditionalRegion defines the subnets, security groups and AMIs used to launch nodes in a region other than the
+// cluster's region. Subnets, security groups and AMIs are regional, so they're selected separately in each region.
+type AdditionalRegion struct {
+	// Name of the region
+	// +kubebuilder:validation:Pattern:="^[a-z]{2}(-gov)?-[a-z]+-[0-9]+$"
+	// +required
+	Name string `json:"name"`
+	// SubnetSelectorTerms is a list of or subnet selector terms for the region. The terms are ORed.
+	// +kubebuilder:validation:XValidation:message="subnetSelectorTerms cannot be empty",rule="self.size() != 0"
+	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id']",rule="self.all(x, has(x.tags) || has(x.id))"
+	// +kubebuilder:validation:XValidation:message="'id' is mutually exclusive, cannot be set with a combination of other fields in subnetSelectorTerms",rule="!self.all(x, has(x.id) && has(x.tags))"
+	// +kubebuilder:validation:MaxItems:=30
+	// +required
+	SubnetSelectorTerms []SubnetSelectorTerm `json:"subnetSelectorTerms"`
+	// SecurityGroupSelectorTerms is a list of or security group selector terms for the region. The terms are ORed.
+	// +kubebuilder:validation:XValidation:message="securityGroupSelectorTerms cannot be empty",rule="self.size() != 0"
+	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id', 'name']",rule="self.all(x, has(x.tags) || has(x.id) || has(x.name))"
+	// +kubebuilder:validation:XValidation:message="'id' is mutually exclusive, cannot be set with a combination of other fields in securityGroupSelectorTerms",rule="!self.all(x, has(x.id) && (has(x.tags) || has(x.name)))"
+	// +kubebuilder:validation:XValidation:message="'name' is mutually exclusive, cannot be set with a combination of other fields in securityGroupSelectorTerms",rule="!self.all(x, has(x.name) && (has(x.tags) || has(x.id)))"
+	// +kubebuilder:validation:MaxItems:=30
+	// +required
+	SecurityGroupSelectorTerms []SecurityGroupSelectorTerm `json:"securityGroupSelectorTerms"`
+	// AMISelectorTerms is a list of or ami selector terms for the region. The terms are ORed. If unset, the
+	// EC2NodeClass's amiSelectorTerms are used, which only select AMIs in this region by tags or name.
+	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id', 'name']",rule="self.all(x, has(x.tags) || has(x.id) || has(x.name))"
+	// +kubebuilder:validation:XValidation:message="'id' is mutually exclusive, cannot be set with a combination of other fields in amiSelectorTerms",rule="!self.all(x, has(x.id) && (has(x.tags) || has(x.name) || has(x.owner)))"
+	// +kubebuilder:validation:MaxItems:=30
+	// +optional
+	AMISelectorTerms []AMISelectorTerm `json:"amiSelectorTerms,omitempty"`
 }
 
 // SubnetSelectorTerm defines selection logic for a subnet used by Karpenter to launch nodes.
@@ -316,6 +359,63 @@ const (
 	InstanceStorePolicyRAID0 InstanceStorePolicy = "RAID0"
 )
 
+// CarbonPolicy configures carbon-aware selection of offerings.
+// +kubebuilder:validation:XValidation:message="carbonPrice is required when mode is Weighted",rule="self.mode == 'Weighted' ? has(self.carbonPrice) : true"
+// +kubebuilder:validation:XValidation:message="maxIntensity is required when mode is Strict",rule="self.mode == 'Strict' ? has(self.maxIntensity) : true"
+// +kubebuilder:validation:XValidation:message="maxIntensity is required when driftAfter is set",rule="has(self.driftAfter) ? has(self.maxIntensity) : true"
+type CarbonPolicy struct {
+	// Mode controls how carbon emissions affect the choice of offerings. Off ignores emissions, Weighted adds the cost
+	// of estimated emissions to offering prices and Strict additionally refuses offerings where the grid intensity is
+	// above maxIntensity.
+	// +required
+	Mode CarbonMode `json:"mode"`
+	// CarbonPrice is the price, in US dollars per metric ton of CO2 equivalent, used to weigh estimated emissions
+	// against cost. The estimated hourly emissions of each offering, priced at this rate, are added to the offering's
+	// price so that Karpenter prefers greener offerings when the difference in emissions outweighs the difference in price.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	CarbonPrice *int64 `json:"carbonPrice,omitempty"`
+	// MaxIntensity is the highest grid carbon intensity, in grams of CO2 equivalent per kWh, that offerings may be
+	// launched at when mode is Strict. It is also the ceiling above which nodes drift when driftAfter is set.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	MaxIntensity *int64 `json:"maxIntensity,omitempty"`
+	// IncludeEmbodied includes the amortized manufacturing emissions of the host in the estimated emissions of an
+	// offering, in addition to the operational emissions of running it.
+	// +optional
+	IncludeEmbodied *bool `json:"includeEmbodied,omitempty"`
+	// PreferEfficientInstanceTypes orders instance types by their estimated work per watt, favoring newer generations
+	// and Graviton, before the instance types in a launch request are truncated to the most that EC2 accepts. By default,
+	// the cheapest instance types are kept.
+	// +optional
+	PreferEfficientInstanceTypes *bool `json:"preferEfficientInstanceTypes,omitempty"`
+	// DriftOnChange drifts nodes launched with this EC2NodeClass when the carbon policy changes. By default, changes
+	// to the carbon policy only affect new nodes.
+	// +optional
+	DriftOnChange *bool `json:"driftOnChange,omitempty"`
+	// DriftAfter drifts nodes whose zone has stayed above maxIntensity for at least this long while a compatible
+	// offering below maxIntensity exists, so that workloads gradually move onto greener capacity. Nodes don't drift
+	// for carbon intensity if this is unset or mode is Off.
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +optional
+	DriftAfter *metav1.Duration `json:"driftAfter,omitempty"`
+}
+
+// CarbonMode enumerates the ways in which carbon emissions affect the choice of offerings.
+// +kubebuilder:validation:Enum={Off,Weighted,Strict}
+type CarbonMode string
+
+const (
+	// CarbonModeOff ignores carbon emissions when choosing offerings.
+	CarbonModeOff CarbonMode = "Off"
+	// CarbonModeWeighted adds the cost of estimated emissions, at the carbon price, to offering prices.
+	CarbonModeWeighted CarbonMode = "Weighted"
+	// CarbonModeStrict weighs offerings like CarbonModeWeighted and also refuses offerings where the grid intensity is
+	// above the maximum intensity.
+	CarbonModeStrict CarbonMode = "Strict"
+)
+
 // EC2NodeClass is the Schema for the EC2NodeClass API
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=ec2nodeclasses,scope=Cluster,categories=karpenter,shortName={ec2nc,ec2ncs}
@@ -338,11 +438,20 @@ type EC2NodeClass struct {
 const EC2NodeClassHashVersion = "v2"
 
 func (in *EC2NodeClass) Hash() string {
-	return fmt.Sprint(lo.Must(hashstructure.Hash(in.Spec, hashstructure.FormatV2, &hashstructure.HashOptions{
+	hash := lo.Must(hashstructure.Hash(in.Spec, hashstructure.FormatV2, &hashstructure.HashOptions{
 		SlicesAsSets:    true,
 		IgnoreZeroValue: true,
 		ZeroNil:         true,
-	})))
+	}))
+	// The carbon policy is excluded from the spec hash so that tuning it doesn't replace every node. Users that want
+	// their nodes to follow the policy can opt in, which only changes the hash of EC2NodeClasses that opt in.
+	if in.Spec.CarbonPolicy != nil && lo.FromPtr(in.Spec.CarbonPolicy.DriftOnChange) {
+		return fmt.Sprintf("%d-%d", hash, lo.Must(hashstructure.Hash(in.Spec.CarbonPolicy, hashstructure.FormatV2, &hashstructure.HashOptions{
+			IgnoreZeroValue: true,
+			ZeroNil:         true,
+		})))
+	}
+	return fmt.Sprint(hash)
 }
 
 func (in *EC2NodeClass) InstanceProfileName(clusterName, region string) string {
@@ -201,6 +201,30 @@ var _ = Describe("Hash", func() {
 		updatedHash := nodeClass.Hash()
 		Expect(hash).To(Equal(updatedHash))
 	})
+	It("should not change hash when carbonPolicy is updated", func() {
+		hash := nodeClass.Hash()
+		nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+		Expect(nodeClass.Hash()).To(Equal(hash))
+		nodeClass.Spec.CarbonPolicy.CarbonPrice = lo.ToPtr[int64](200)
+		Expect(nodeClass.Hash()).To(Equal(hash))
+	})
+	It("should change hash when carbonPolicy is updated and driftOnChange is enabled", func() {
+		nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100), DriftOnChange: lo.ToPtr(true)}
+		hash := nodeClass.Hash()
+		nodeClass.Spec.CarbonPolicy.CarbonPrice = lo.ToPtr[int64](200)
+		Expect(nodeClass.Hash()).ToNot(Equal(hash))
+	})
+	It("should not change hash when additionalRegions are updated", func() {
+		hash := nodeClass.Hash()
+		nodeClass.Spec.AdditionalRegions = []v1beta1.AdditionalRegion{
+			{
+				Name:                       "eu-north-1",
+				SubnetSelectorTerms:        []v1beta1.SubnetSelectorTerm{{Tags: map[string]string{"*": "*"}}},
+				SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{{Tags: map[string]string{"*": "*"}}},
+			},
+		}
+		Expect(nodeClass.Hash()).To(Equal(hash))
+	})
 	It("should expect two EC2NodeClasses with the same spec to have the same hash", func() {
 		otherNodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
 			Spec: nodeClass.Spec,
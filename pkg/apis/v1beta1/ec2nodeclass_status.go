@@ -55,6 +55,25 @@ type AMI struct {
 	Requirements []v1.NodeSelectorRequirement `json:"requirements"`
 }
 
+// AdditionalRegionStatus contains the resolved selector values of an additional region utilized for node launch
+type AdditionalRegionStatus struct {
+	// Name of the region
+	// +required
+	Name string `json:"name"`
+	// Subnets contains the current Subnet values that are available to the
+	// cluster in the region under the region's subnet selectors.
+	// +optional
+	Subnets []Subnet `json:"subnets,omitempty"`
+	// SecurityGroups contains the current Security Groups values that are available to the
+	// cluster in the region under the region's SecurityGroups selectors.
+	// +optional
+	SecurityGroups []SecurityGroup `json:"securityGroups,omitempty"`
+	// AMI contains the current AMI values that are available to the
+	// cluster in the region under the AMI selectors.
+	// +optional
+	AMIs []AMI `json:"amis,omitempty"`
+}
+
 // EC2NodeClassStatus contains the resolved state of the EC2NodeClass
 type EC2NodeClassStatus struct {
 	// Subnets contains the current Subnet values that are available to the
@@ -72,11 +91,19 @@ type EC2NodeClassStatus struct {
 	// InstanceProfile contains the resolved instance profile for the role
 	// +optional
 	InstanceProfile string `json:"instanceProfile,omitempty"`
+	// AdditionalRegions contains the resolved subnets, security groups and AMIs of each of the
+	// EC2NodeClass's additional regions.
+	// +optional
+	AdditionalRegions []AdditionalRegionStatus `json:"additionalRegions,omitempty"`
 	// Conditions contains signals for health and readiness
 	// +optional
 	Conditions []status.Condition `json:"conditions,omitempty"`
 }
 
+// ConditionTypeCarbonDataReady reports whether the carbon intensity data that a carbon aware EC2NodeClass launches on is
+// up to date. It only affects readiness when the carbon policy is Strict.
+const ConditionTypeCarbonDataReady = "CarbonDataReady"
+
 func (in *EC2NodeClass) StatusConditions() status.ConditionSet {
 	return status.NewReadyConditions().For(in)
 }
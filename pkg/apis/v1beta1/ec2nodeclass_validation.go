@@ -35,6 +35,7 @@ const (
 	blockDeviceMappingsPath        = "blockDeviceMappings"
 	rolePath                       = "role"
 	instanceProfilePath            = "instanceProfile"
+	carbonPolicyPath               = "carbonPolicy"
 )
 
 var (
@@ -81,6 +82,7 @@ func (in *EC2NodeClassSpec) validate(_ context.Context) (errs *apis.FieldError)
 		in.validateAMIFamily().ViaField(amiFamilyPath),
 		in.validateBlockDeviceMappings().ViaField(blockDeviceMappingsPath),
 		in.validateTags().ViaField(tagsPath),
+		in.validateCarbonPolicy().ViaField(carbonPolicyPath),
 	)
 }
 
@@ -296,6 +298,35 @@ func (in *EC2NodeClassSpec) validateTags() (errs *apis.FieldError) {
 	return errs
 }
 
+func (in *EC2NodeClassSpec) validateCarbonPolicy() (errs *apis.FieldError) {
+	if in.CarbonPolicy == nil {
+		return nil
+	}
+	errs = errs.Also(in.validateStringEnum(string(in.CarbonPolicy.Mode), "mode",
+		[]string{string(CarbonModeOff), string(CarbonModeWeighted), string(CarbonModeStrict)}))
+	if in.CarbonPolicy.CarbonPrice != nil && *in.CarbonPolicy.CarbonPrice < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*in.CarbonPolicy.CarbonPrice, "carbonPrice", "carbonPrice cannot be negative"))
+	}
+	if in.CarbonPolicy.MaxIntensity != nil && *in.CarbonPolicy.MaxIntensity < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*in.CarbonPolicy.MaxIntensity, "maxIntensity", "maxIntensity cannot be negative"))
+	}
+	if in.CarbonPolicy.Mode == CarbonModeWeighted && in.CarbonPolicy.CarbonPrice == nil {
+		errs = errs.Also(apis.ErrMissingField("carbonPrice"))
+	}
+	if in.CarbonPolicy.Mode == CarbonModeStrict && in.CarbonPolicy.MaxIntensity == nil {
+		errs = errs.Also(apis.ErrMissingField("maxIntensity"))
+	}
+	if in.CarbonPolicy.DriftAfter != nil {
+		if in.CarbonPolicy.DriftAfter.Duration <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(in.CarbonPolicy.DriftAfter.Duration.String(), "driftAfter", "driftAfter must be positive"))
+		}
+		if in.CarbonPolicy.MaxIntensity == nil {
+			errs = errs.Also(apis.ErrMissingField("maxIntensity"))
+		}
+	}
+	return errs
+}
+
 func (in *EC2NodeClassSpec) validateRoleImmutability(originalSpec *EC2NodeClassSpec) *apis.FieldError {
 	if in.Role != originalSpec.Role {
 		return &apis.FieldError{
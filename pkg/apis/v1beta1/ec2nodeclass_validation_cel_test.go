@@ -15,9 +15,12 @@ limitations under the License.
 package v1beta1_test
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
@@ -622,6 +625,84 @@ var _ = Describe("CEL/Validation", func() {
 			Expect(env.Client.Create(ctx, nodeClass)).To(Not(Succeed()))
 		})
 	})
+	Context("CarbonPolicy", func() {
+		It("should succeed if carbon policy is not specified", func() {
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed when mode is Off without other fields", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeOff}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed when mode is Weighted with a carbon price", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed when mode is Strict with a max intensity", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](300)}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when mode is not a known value", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: "Greedy"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when mode is Weighted without a carbon price", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when mode is Strict without a max intensity", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when carbon price is negative", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](-1)}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when max intensity is negative", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](-1)}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should succeed when driftAfter is set with a max intensity", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100), MaxIntensity: lo.ToPtr[int64](300), DriftAfter: &metav1.Duration{Duration: time.Hour}}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when driftAfter is set without a max intensity", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100), DriftAfter: &metav1.Duration{Duration: time.Hour}}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("AdditionalRegions", func() {
+		var additionalRegion v1beta1.AdditionalRegion
+		BeforeEach(func() {
+			additionalRegion = v1beta1.AdditionalRegion{
+				Name:                       "eu-north-1",
+				SubnetSelectorTerms:        []v1beta1.SubnetSelectorTerm{{Tags: map[string]string{"*": "*"}}},
+				SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{{Tags: map[string]string{"*": "*"}}},
+			}
+		})
+		It("should succeed with an additional region", func() {
+			nc.Spec.AdditionalRegions = []v1beta1.AdditionalRegion{additionalRegion}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when the region name isn't a region", func() {
+			additionalRegion.Name = "eu-north-1a"
+			nc.Spec.AdditionalRegions = []v1beta1.AdditionalRegion{additionalRegion}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when a region is specified more than once", func() {
+			nc.Spec.AdditionalRegions = []v1beta1.AdditionalRegion{additionalRegion, additionalRegion}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when the region has no subnet selector terms", func() {
+			additionalRegion.SubnetSelectorTerms = nil
+			nc.Spec.AdditionalRegions = []v1beta1.AdditionalRegion{additionalRegion}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when the region has an empty security group selector term", func() {
+			additionalRegion.SecurityGroupSelectorTerms = []v1beta1.SecurityGroupSelectorTerm{{}}
+			nc.Spec.AdditionalRegions = []v1beta1.AdditionalRegion{additionalRegion}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
 	Context("Role Immutability", func() {
 		It("should fail if role is not defined", func() {
 			nc.Spec.Role = ""
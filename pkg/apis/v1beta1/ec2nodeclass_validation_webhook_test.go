@@ -15,8 +15,11 @@ limitations under the License.
 package v1beta1_test
 
 import (
+	"time"
+
 	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -492,6 +495,55 @@ var _ = Describe("Webhook/Validation", func() {
 			Expect(nodeClass.Validate(ctx)).To(Not(Succeed()))
 		})
 	})
+	Context("CarbonPolicy", func() {
+		It("should succeed if carbon policy is not specified", func() {
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should succeed when mode is Off without other fields", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeOff}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should succeed when mode is Weighted with a carbon price", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should succeed when mode is Strict with a max intensity", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](300)}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail when mode is not a known value", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: "Greedy"}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when mode is Weighted without a carbon price", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when mode is Strict without a max intensity", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when carbon price is negative", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](-1)}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when max intensity is negative", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](-1)}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should succeed when driftAfter is set with a max intensity", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100), MaxIntensity: lo.ToPtr[int64](300), DriftAfter: &metav1.Duration{Duration: time.Hour}}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail when driftAfter is set without a max intensity", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100), DriftAfter: &metav1.Duration{Duration: time.Hour}}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when driftAfter is negative", func() {
+			nc.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](300), DriftAfter: &metav1.Duration{Duration: -time.Hour}}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+	})
 	Context("Role Immutability", func() {
 		It("should fail when updating the role", func() {
 			nc.Spec.Role = "test-role"
@@ -46,13 +46,19 @@ func init() {
 		LabelInstanceAcceleratorName,
 		LabelInstanceAcceleratorManufacturer,
 		LabelInstanceAcceleratorCount,
+		LabelInstanceWattsMax,
+		LabelInstanceEmbodiedKgCO2e,
+		LabelCarbonCritical,
 		LabelTopologyZoneID,
+		LabelTopologyZoneCarbonTier,
 		v1.LabelWindowsBuild,
 	)
 }
 
 var (
-	TerminationFinalizer   = Group + "/termination"
+	TerminationFinalizer = Group + "/termination"
+	// EmissionsFinalizer holds a NodeClaim until its final emissions have been accounted for
+	EmissionsFinalizer     = Group + "/emissions"
 	AWSToKubeArchitectures = map[string]string{
 		"x86_64":                  v1beta1.ArchitectureAmd64,
 		v1beta1.ArchitectureArm64: v1beta1.ArchitectureArm64,
@@ -95,7 +101,8 @@ var (
 
 	LabelNodeClass = Group + "/ec2nodeclass"
 
-	LabelTopologyZoneID = "topology.k8s.aws/zone-id"
+	LabelTopologyZoneID         = "topology.k8s.aws/zone-id"
+	LabelTopologyZoneCarbonTier = "topology.k8s.aws/zone-carbon-tier"
 
 	LabelInstanceHypervisor                   = Group + "/instance-hypervisor"
 	LabelInstanceEncryptionInTransitSupported = Group + "/instance-encryption-in-transit-supported"
@@ -116,11 +123,30 @@ var (
 	LabelInstanceAcceleratorName              = Group + "/instance-accelerator-name"
 	LabelInstanceAcceleratorManufacturer      = Group + "/instance-accelerator-manufacturer"
 	LabelInstanceAcceleratorCount             = Group + "/instance-accelerator-count"
+	LabelInstanceWattsMax                     = Group + "/instance-watts-max"
+	LabelInstanceEmbodiedKgCO2e               = Group + "/instance-embodied-kgco2e"
+	LabelCarbonCritical                       = Group + "/carbon-critical"
 	AnnotationEC2NodeClassHash                = Group + "/ec2nodeclass-hash"
 	AnnotationEC2NodeClassHashVersion         = Group + "/ec2nodeclass-hash-version"
 	AnnotationInstanceTagged                  = Group + "/tagged"
+	AnnotationEmissions                       = Group + "/emissions-gco2e"
+	AnnotationEmissionsAccountedAt            = Group + "/emissions-accounted-at"
+	AnnotationEmissionsUnbudgeted             = Group + "/emissions-unbudgeted-gco2e"
+	AnnotationCarbonMaxDeferral               = Group + "/carbon-max-deferral"
+	AnnotationCarbonBudget                    = Group + "/carbon-budget-kgco2e"
+	AnnotationCarbonBudgetPeriod              = Group + "/carbon-budget-period"
+	AnnotationCarbonBudgetAction              = Group + "/carbon-budget-action"
+	AnnotationCarbonBudgetPeriodStart         = Group + "/carbon-budget-period-start"
+	AnnotationCarbonBudgetConsumed            = Group + "/carbon-budget-consumed-kgco2e"
+	AnnotationCarbonBudgetRemaining           = Group + "/carbon-budget-remaining-kgco2e"
+	AnnotationCarbonIntensityAtLaunch         = Group + "/carbon-intensity-at-launch"
+	AnnotationEstimatedWatts                  = Group + "/estimated-watts"
+	AnnotationCarbonMode                      = Group + "/carbon-mode"
 
-	TagNodeClaim             = v1beta1.Group + "/nodeclaim"
-	TagManagedLaunchTemplate = Group + "/cluster"
-	TagName                  = "Name"
+	TagNodeClaim               = v1beta1.Group + "/nodeclaim"
+	TagManagedLaunchTemplate   = Group + "/cluster"
+	TagName                    = "Name"
+	TagCarbonIntensityAtLaunch = Group + "/carbon-intensity-at-launch"
+	TagEstimatedWatts          = Group + "/estimated-watts"
+	TagCarbonMode              = Group + "/carbon-mode"
 )
@@ -20,7 +20,8 @@ package v1beta1
 
 import (
 	"github.com/awslabs/operatorpkg/status"
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -29,7 +30,7 @@ func (in *AMI) DeepCopyInto(out *AMI) {
 	*out = *in
 	if in.Requirements != nil {
 		in, out := &in.Requirements, &out.Requirements
-		*out = make([]v1.NodeSelectorRequirement, len(*in))
+		*out = make([]corev1.NodeSelectorRequirement, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -68,6 +69,74 @@ func (in *AMISelectorTerm) DeepCopy() *AMISelectorTerm {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalRegion) DeepCopyInto(out *AdditionalRegion) {
+	*out = *in
+	if in.SubnetSelectorTerms != nil {
+		in, out := &in.SubnetSelectorTerms, &out.SubnetSelectorTerms
+		*out = make([]SubnetSelectorTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SecurityGroupSelectorTerms != nil {
+		in, out := &in.SecurityGroupSelectorTerms, &out.SecurityGroupSelectorTerms
+		*out = make([]SecurityGroupSelectorTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AMISelectorTerms != nil {
+		in, out := &in.AMISelectorTerms, &out.AMISelectorTerms
+		*out = make([]AMISelectorTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalRegion.
+func (in *AdditionalRegion) DeepCopy() *AdditionalRegion {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalRegion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalRegionStatus) DeepCopyInto(out *AdditionalRegionStatus) {
+	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]Subnet, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]SecurityGroup, len(*in))
+		copy(*out, *in)
+	}
+	if in.AMIs != nil {
+		in, out := &in.AMIs, &out.AMIs
+		*out = make([]AMI, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalRegionStatus.
+func (in *AdditionalRegionStatus) DeepCopy() *AdditionalRegionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalRegionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlockDevice) DeepCopyInto(out *BlockDevice) {
 	*out = *in
@@ -148,6 +217,51 @@ func (in *BlockDeviceMapping) DeepCopy() *BlockDeviceMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CarbonPolicy) DeepCopyInto(out *CarbonPolicy) {
+	*out = *in
+	if in.CarbonPrice != nil {
+		in, out := &in.CarbonPrice, &out.CarbonPrice
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxIntensity != nil {
+		in, out := &in.MaxIntensity, &out.MaxIntensity
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IncludeEmbodied != nil {
+		in, out := &in.IncludeEmbodied, &out.IncludeEmbodied
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreferEfficientInstanceTypes != nil {
+		in, out := &in.PreferEfficientInstanceTypes, &out.PreferEfficientInstanceTypes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DriftOnChange != nil {
+		in, out := &in.DriftOnChange, &out.DriftOnChange
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DriftAfter != nil {
+		in, out := &in.DriftAfter, &out.DriftAfter
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CarbonPolicy.
+func (in *CarbonPolicy) DeepCopy() *CarbonPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CarbonPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EC2NodeClass) DeepCopyInto(out *EC2NodeClass) {
 	*out = *in
@@ -289,6 +403,18 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CarbonPolicy != nil {
+		in, out := &in.CarbonPolicy, &out.CarbonPolicy
+		*out = new(CarbonPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalRegions != nil {
+		in, out := &in.AdditionalRegions, &out.AdditionalRegions
+		*out = make([]AdditionalRegion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EC2NodeClassSpec.
@@ -321,6 +447,13 @@ func (in *EC2NodeClassStatus) DeepCopyInto(out *EC2NodeClassStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AdditionalRegions != nil {
+		in, out := &in.AdditionalRegions, &out.AdditionalRegions
+		*out = make([]AdditionalRegionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]status.Condition, len(*in))
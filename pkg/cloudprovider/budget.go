@@ -0,0 +1,90 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	cloudproviderevents "github.com/aws/karpenter-provider-aws/pkg/cloudprovider/events"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+)
+
+// enforceCarbonBudget returns the EC2NodeClass to launch a NodeClaim with, which has its carbon mode escalated to Strict
+// once the carbon budget of the NodeClaim's NodePool is exhausted. It returns an error, so that the launch is retried,
+// if the budget refuses non-critical launches and the NodeClaim doesn't require the carbon-critical label.
+func (c *CloudProvider) enforceCarbonBudget(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass) (*v1beta1.EC2NodeClass, error) {
+	nodePoolName, ok := nodeClaim.Labels[corev1beta1.NodePoolLabelKey]
+	if !ok {
+		return nodeClass, nil
+	}
+	nodePool := &corev1beta1.NodePool{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodePoolName}, nodePool); err != nil {
+		return nodeClass, client.IgnoreNotFound(fmt.Errorf("resolving nodepool, %w", err))
+	}
+	budget := c.exhaustedCarbonBudget(ctx, nodePool)
+	if budget == nil {
+		return nodeClass, nil
+	}
+	switch budget.Action {
+	case carbon.BudgetActionRefuseNonCritical:
+		if scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Get(v1beta1.LabelCarbonCritical).Has("true") {
+			return nodeClass, nil
+		}
+		c.recorder.Publish(cloudproviderevents.NodeClaimCarbonBudgetExhausted(nodeClaim, budget.KgCO2e))
+		return nil, fmt.Errorf("carbon budget of %.2f kgCO2e for nodepool %s is exhausted, only launching nodeclaims requiring %s=true",
+			budget.KgCO2e, nodePoolName, v1beta1.LabelCarbonCritical)
+	default:
+		return escalateCarbonMode(ctx, nodeClass), nil
+	}
+}
+
+// exhaustedCarbonBudget returns the carbon budget of a NodePool if it's exhausted, or nil if it isn't or the NodePool
+// has no budget
+func (c *CloudProvider) exhaustedCarbonBudget(ctx context.Context, nodePool *corev1beta1.NodePool) *carbon.Budget {
+	budget, err := carbon.NodePoolBudget(nodePool)
+	if err != nil {
+		log.FromContext(ctx).WithValues("nodepool", nodePool.Name).Error(err, "failed parsing carbon budget, launching without enforcing it")
+		return nil
+	}
+	if budget == nil || !budget.Exhausted(c.clk.Now()) {
+		return nil
+	}
+	return budget
+}
+
+// escalateCarbonMode returns a copy of an EC2NodeClass in Strict carbon mode. Strict mode needs a maximum intensity, so
+// an EC2NodeClass without one is returned as is.
+func escalateCarbonMode(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) *v1beta1.EC2NodeClass {
+	policy := nodeClass.Spec.CarbonPolicy
+	if policy == nil || policy.MaxIntensity == nil {
+		log.FromContext(ctx).WithValues("ec2nodeclass", nodeClass.Name).V(1).Info("carbon budget is exhausted but the carbon policy has no maxIntensity, not escalating to Strict")
+		return nodeClass
+	}
+	if policy.Mode == v1beta1.CarbonModeStrict {
+		return nodeClass
+	}
+	escalated := nodeClass.DeepCopy()
+	escalated.Spec.CarbonPolicy.Mode = v1beta1.CarbonModeStrict
+	return escalated
+}
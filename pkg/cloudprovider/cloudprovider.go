@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -25,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/clock"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
@@ -44,8 +47,10 @@ import (
 
 	cloudproviderevents "github.com/aws/karpenter-provider-aws/pkg/cloudprovider/events"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
@@ -56,22 +61,33 @@ var _ cloudprovider.CloudProvider = (*CloudProvider)(nil)
 type CloudProvider struct {
 	kubeClient client.Client
 	recorder   events.Recorder
+	clk        clock.Clock
 
 	instanceTypeProvider  instancetype.Provider
 	instanceProvider      instance.Provider
 	amiProvider           amifamily.Provider
 	securityGroupProvider securitygroup.Provider
+	carbonProvider        carbon.Provider
+	regionProvider        region.Provider
+
+	muCarbonDrift         sync.Mutex
+	carbonCeilingExceeded map[string]time.Time
 }
 
 func New(instanceTypeProvider instancetype.Provider, instanceProvider instance.Provider, recorder events.Recorder,
-	kubeClient client.Client, amiProvider amifamily.Provider, securityGroupProvider securitygroup.Provider) *CloudProvider {
+	kubeClient client.Client, amiProvider amifamily.Provider, securityGroupProvider securitygroup.Provider, carbonProvider carbon.Provider,
+	regionProvider region.Provider, clk clock.Clock) *CloudProvider {
 	return &CloudProvider{
 		instanceTypeProvider:  instanceTypeProvider,
 		instanceProvider:      instanceProvider,
 		kubeClient:            kubeClient,
 		amiProvider:           amiProvider,
 		securityGroupProvider: securityGroupProvider,
+		carbonProvider:        carbonProvider,
+		regionProvider:        regionProvider,
 		recorder:              recorder,
+		clk:                   clk,
+		carbonCeilingExceeded: map[string]time.Time{},
 	}
 }
 
@@ -89,33 +105,81 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *corev1beta1.NodeC
 	if !nodeClassReady.IsTrue() {
 		return nil, fmt.Errorf("resolving ec2nodeclass, %s", nodeClassReady.Message)
 	}
-	instanceTypes, err := c.resolveInstanceTypes(ctx, nodeClaim, nodeClass)
+	// Enforcing a carbon budget may escalate the carbon policy we launch with, but the NodeClaim is annotated with the
+	// hash of the EC2NodeClass as written so that the budget doesn't drift the node
+	launchNodeClass, err := c.enforceCarbonBudget(ctx, nodeClaim, nodeClass)
+	if err != nil {
+		return nil, err
+	}
+	instanceTypes, err := c.resolveInstanceTypes(ctx, nodeClaim, launchNodeClass)
 	if err != nil {
 		return nil, fmt.Errorf("resolving instance types, %w", err)
 	}
 	if len(instanceTypes) == 0 {
 		return nil, cloudprovider.NewInsufficientCapacityError(fmt.Errorf("all requested instance types were unavailable during launch"))
 	}
-	instance, err := c.instanceProvider.Create(ctx, nodeClass, nodeClaim, instanceTypes)
+	if err = c.deferForLowerCarbon(ctx, nodeClaim, instanceTypes); err != nil {
+		return nil, err
+	}
+	instanceProvider, launchNodeClass, instanceTypes, err := c.launchRegion(ctx, nodeClaim, launchNodeClass, instanceTypes)
+	if err != nil {
+		return nil, fmt.Errorf("resolving launch region, %w", err)
+	}
+	instance, err := instanceProvider.Create(ctx, launchNodeClass, nodeClaim, instanceTypes)
 	if err != nil {
 		return nil, fmt.Errorf("creating instance, %w", err)
 	}
 	instanceType, _ := lo.Find(instanceTypes, func(i *cloudprovider.InstanceType) bool {
 		return i.Name == instance.Type
 	})
-	nc := c.instanceToNodeClaim(instance, instanceType, nodeClass)
+	nc := c.instanceToNodeClaim(instance, instanceType, launchNodeClass)
 	nc.Annotations = lo.Assign(nodeClass.Annotations, map[string]string{
 		v1beta1.AnnotationEC2NodeClassHash:        nodeClass.Hash(),
 		v1beta1.AnnotationEC2NodeClassHashVersion: v1beta1.EC2NodeClassHashVersion,
-	})
+	}, c.carbonAnnotations(nc, launchNodeClass))
 	return nc, nil
 }
 
+// carbonAnnotations records the carbon intensity of the grid, the estimated draw of the instance and the carbon mode
+// that a NodeClaim was launched with, so that they can be tagged on the instance and joined to its billing. They're only
+// recorded for EC2NodeClasses that opt into a carbon mode, so that instances of other EC2NodeClasses aren't tagged with
+// keys that the controller's tagging policy may not allow.
+func (c *CloudProvider) carbonAnnotations(nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass) map[string]string {
+	if nodeClass.Spec.CarbonPolicy == nil || nodeClass.Spec.CarbonPolicy.Mode == v1beta1.CarbonModeOff {
+		return nil
+	}
+	annotations := map[string]string{
+		v1beta1.AnnotationCarbonMode: string(nodeClass.Spec.CarbonPolicy.Mode),
+	}
+	if intensity, ok := c.carbonProvider.ZoneIntensity(nodeClaim.Labels[v1.LabelTopologyZone]); ok {
+		annotations[v1beta1.AnnotationCarbonIntensityAtLaunch] = strconv.FormatFloat(intensity, 'f', 0, 64)
+	}
+	if info, ok := instancetype.InstanceTypeInfoFromLabels(nodeClaim.Labels); ok {
+		annotations[v1beta1.AnnotationEstimatedWatts] = strconv.FormatFloat(instancetype.ComputePower(info).Watts(instancetype.AverageUtilization), 'f', 0, 64)
+	}
+	return annotations
+}
+
 func (c *CloudProvider) List(ctx context.Context) ([]*corev1beta1.NodeClaim, error) {
 	instances, err := c.instanceProvider.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("listing instances, %w", err)
 	}
+	regions, err := c.additionalRegions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing additional regions, %w", err)
+	}
+	for _, regionName := range regions {
+		providers, err := c.regionProvider.Get(ctx, regionName)
+		if err != nil {
+			return nil, fmt.Errorf("getting providers for region %s, %w", regionName, err)
+		}
+		regionalInstances, err := providers.InstanceProvider.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing instances in region %s, %w", regionName, err)
+		}
+		instances = append(instances, regionalInstances...)
+	}
 	var nodeClaims []*corev1beta1.NodeClaim
 	for _, instance := range instances {
 		instanceType, err := c.resolveInstanceTypeFromInstance(ctx, instance)
@@ -137,7 +201,11 @@ func (c *CloudProvider) Get(ctx context.Context, providerID string) (*corev1beta
 		return nil, fmt.Errorf("getting instance ID, %w", err)
 	}
 	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("id", id))
-	instance, err := c.instanceProvider.Get(ctx, id)
+	instanceProvider, err := c.instanceProviderForProviderID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := instanceProvider.Get(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("getting instance, %w", err)
 	}
@@ -168,11 +236,21 @@ func (c *CloudProvider) GetInstanceTypes(ctx context.Context, nodePool *corev1be
 		// as the cause.
 		return nil, fmt.Errorf("resolving node class, %w", err)
 	}
+	// Scheduling must see the same offerings that Create launches from once a carbon budget escalates to Strict
+	if budget := c.exhaustedCarbonBudget(ctx, nodePool); budget != nil && budget.Action == carbon.BudgetActionStrict {
+		nodeClass = escalateCarbonMode(ctx, nodeClass)
+	}
 	// TODO, break this coupling
-	instanceTypes, err := c.instanceTypeProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+	instanceTypes, err := c.listInstanceTypes(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
 	if err != nil {
 		return nil, err
 	}
+	// A strict carbon ceiling can leave nothing to launch, so we explain why pods aren't scheduling rather than
+	// leaving users to work out that the grid is too carbon intensive
+	if policy := nodeClass.Spec.CarbonPolicy; policy != nil && policy.Mode == v1beta1.CarbonModeStrict && policy.MaxIntensity != nil &&
+		!lo.ContainsBy(instanceTypes, func(i *cloudprovider.InstanceType) bool { return len(i.Offerings.Available()) > 0 }) {
+		c.recorder.Publish(cloudproviderevents.NodeClassCarbonCeilingExceeded(nodeClass, *policy.MaxIntensity))
+	}
 	return instanceTypes, nil
 }
 
@@ -182,7 +260,11 @@ func (c *CloudProvider) Delete(ctx context.Context, nodeClaim *corev1beta1.NodeC
 		return fmt.Errorf("getting instance ID, %w", err)
 	}
 	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("id", id))
-	return c.instanceProvider.Delete(ctx, id)
+	instanceProvider, err := c.instanceProviderForProviderID(ctx, nodeClaim.Status.ProviderID)
+	if err != nil {
+		return err
+	}
+	return instanceProvider.Delete(ctx, id)
 }
 
 func (c *CloudProvider) IsDrifted(ctx context.Context, nodeClaim *corev1beta1.NodeClaim) (cloudprovider.DriftReason, error) {
@@ -255,7 +337,7 @@ func (c *CloudProvider) resolveNodeClassFromNodePool(ctx context.Context, nodePo
 }
 
 func (c *CloudProvider) resolveInstanceTypes(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass) ([]*cloudprovider.InstanceType, error) {
-	instanceTypes, err := c.instanceTypeProvider.List(ctx, nodeClaim.Spec.Kubelet, nodeClass)
+	instanceTypes, err := c.listInstanceTypes(ctx, nodeClaim.Spec.Kubelet, nodeClass)
 	if err != nil {
 		return nil, fmt.Errorf("getting instance types, %w", err)
 	}
@@ -341,6 +423,15 @@ func (c *CloudProvider) instanceToNodeClaim(i *instance.Instance, instanceType *
 			labels[v1beta1.LabelTopologyZoneID] = subnet.ZoneID
 		}
 	}
+	// The instance type may span zones with different carbon tiers, so we resolve the tier of the zone the instance was
+	// launched into from its offerings.
+	if instanceType != nil {
+		if offering, ok := lo.Find(instanceType.Offerings, func(o cloudprovider.Offering) bool {
+			return o.Requirements.Get(v1.LabelTopologyZone).Any() == i.Zone
+		}); ok && offering.Requirements.Has(v1beta1.LabelTopologyZoneCarbonTier) {
+			labels[v1beta1.LabelTopologyZoneCarbonTier] = offering.Requirements.Get(v1beta1.LabelTopologyZoneCarbonTier).Any()
+		}
+	}
 	labels[corev1beta1.CapacityTypeLabelKey] = i.CapacityType
 	if v, ok := i.Tags[corev1beta1.NodePoolLabelKey]; ok {
 		labels[corev1beta1.NodePoolLabelKey] = v
@@ -0,0 +1,71 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	cloudproviderevents "github.com/aws/karpenter-provider-aws/pkg/cloudprovider/events"
+)
+
+// deferForLowerCarbon returns an error, so that the launch is retried later, if the NodeClaim's NodePool is delay
+// tolerant and the forecast shows a much lower carbon intensity within the NodePool's maximum deferral. The maximum
+// deferral is measured from the NodeClaim's creation, so that no NodeClaim is deferred for longer than it, however many
+// times its launch is retried or the controller is restarted.
+func (c *CloudProvider) deferForLowerCarbon(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) error {
+	nodePoolName, ok := nodeClaim.Labels[corev1beta1.NodePoolLabelKey]
+	if !ok {
+		return nil
+	}
+	nodePool := &corev1beta1.NodePool{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodePoolName}, nodePool); err != nil {
+		return client.IgnoreNotFound(fmt.Errorf("resolving nodepool, %w", err))
+	}
+	value, ok := nodePool.Annotations[v1beta1.AnnotationCarbonMaxDeferral]
+	if !ok {
+		return nil
+	}
+	maxDeferral, err := time.ParseDuration(value)
+	if err != nil {
+		log.FromContext(ctx).WithValues("nodepool", nodePoolName).Error(err, "failed parsing carbon max deferral, launching without deferral")
+		return nil
+	}
+	reqs := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...)
+	zones := lo.Uniq(lo.FlatMap(instanceTypes, func(i *cloudprovider.InstanceType, _ int) []string {
+		return lo.Map(i.Offerings.Compatible(reqs).Available(), func(o cloudprovider.Offering, _ int) string {
+			return o.Requirements.Get(v1.LabelTopologyZone).Any()
+		})
+	}))
+	current, window, ok := c.carbonProvider.GreenWindow(zones, c.clk.Now(), nodeClaim.CreationTimestamp.Add(maxDeferral))
+	if !ok {
+		return nil
+	}
+	c.recorder.Publish(cloudproviderevents.NodeClaimLaunchDeferred(nodeClaim, current, window.CarbonIntensity, window.Datetime))
+	return fmt.Errorf("deferring launch until %s, carbon intensity is forecast to fall from %.0f to %.0f gCO2e/kWh",
+		window.Datetime.Format(time.RFC3339), current, window.CarbonIntensity)
+}
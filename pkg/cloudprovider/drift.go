@@ -17,6 +17,7 @@ package cloudprovider
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
@@ -24,10 +25,12 @@ import (
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
 	"github.com/aws/karpenter-provider-aws/pkg/utils"
 )
 
@@ -36,8 +39,14 @@ const (
 	SubnetDrift        cloudprovider.DriftReason = "SubnetDrift"
 	SecurityGroupDrift cloudprovider.DriftReason = "SecurityGroupDrift"
 	NodeClassDrift     cloudprovider.DriftReason = "NodeClassDrift"
+	CarbonDrift        cloudprovider.DriftReason = "CarbonDrift"
+	RegionDrift        cloudprovider.DriftReason = "RegionDrift"
 )
 
+// carbonDriftHysteresis is the fraction below the carbon ceiling that a zone's intensity must fall to before the zone is
+// considered clean again, so that zones hovering around the ceiling don't repeatedly start and stop drifting nodes
+const carbonDriftHysteresis = 0.1
+
 func (c *CloudProvider) isNodeClassDrifted(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, nodePool *corev1beta1.NodePool, nodeClass *v1beta1.EC2NodeClass) (cloudprovider.DriftReason, error) {
 	// First check if the node class is statically drifted to save on API calls.
 	if drifted := c.areStaticFieldsDrifted(nodeClaim, nodeClass); drifted != "" {
@@ -47,6 +56,17 @@ func (c *CloudProvider) isNodeClassDrifted(ctx context.Context, nodeClaim *corev
 	if err != nil {
 		return "", err
 	}
+	// Instances in an additional region are launched with the subnets, security groups and AMIs of that region
+	if regionName, ok := utils.RegionForZone(instance.Zone); ok && regionName != c.regionProvider.Region() {
+		if !lo.ContainsBy(nodeClass.Spec.AdditionalRegions, func(r v1beta1.AdditionalRegion) bool { return r.Name == regionName }) {
+			return RegionDrift, nil
+		}
+		regionalNodeClass, ok := region.NodeClass(nodeClass, regionName)
+		if !ok {
+			return "", fmt.Errorf("region %s isn't resolved", regionName)
+		}
+		nodeClass = regionalNodeClass
+	}
 	amiDrifted, err := c.isAMIDrifted(ctx, nodeClaim, nodePool, instance, nodeClass)
 	if err != nil {
 		return "", fmt.Errorf("calculating ami drift, %w", err)
@@ -59,7 +79,11 @@ func (c *CloudProvider) isNodeClassDrifted(ctx context.Context, nodeClaim *corev
 	if err != nil {
 		return "", fmt.Errorf("calculating subnet drift, %w", err)
 	}
-	drifted := lo.FindOrElse([]cloudprovider.DriftReason{amiDrifted, securitygroupDrifted, subnetDrifted}, "", func(i cloudprovider.DriftReason) bool {
+	carbonDrifted, err := c.isCarbonDrifted(ctx, nodeClaim, nodeClass)
+	if err != nil {
+		return "", fmt.Errorf("calculating carbon drift, %w", err)
+	}
+	drifted := lo.FindOrElse([]cloudprovider.DriftReason{amiDrifted, securitygroupDrifted, subnetDrifted, carbonDrifted}, "", func(i cloudprovider.DriftReason) bool {
 		return string(i) != ""
 	})
 	return drifted, nil
@@ -119,6 +143,58 @@ func (c *CloudProvider) areSecurityGroupsDrifted(ec2Instance *instance.Instance,
 	return "", nil
 }
 
+// Checks if the node is carbon drifted, which is when the intensity of the node's zone has stayed above the carbon
+// ceiling for at least driftAfter while the node could be replaced by an offering in a zone below the ceiling
+func (c *CloudProvider) isCarbonDrifted(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass) (cloudprovider.DriftReason, error) {
+	policy := nodeClass.Spec.CarbonPolicy
+	if policy == nil || policy.Mode == v1beta1.CarbonModeOff || policy.DriftAfter == nil || policy.MaxIntensity == nil {
+		return "", nil
+	}
+	zone, ok := nodeClaim.Labels[v1.LabelTopologyZone]
+	if !ok || !c.hasExceededCarbonCeiling(zone, *policy.MaxIntensity, policy.DriftAfter.Duration) {
+		return "", nil
+	}
+	instanceTypes, err := c.resolveInstanceTypes(ctx, nodeClaim, nodeClass)
+	if err != nil {
+		return "", fmt.Errorf("resolving instance types, %w", err)
+	}
+	reqs := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...)
+	cleanerOfferingExists := lo.ContainsBy(instanceTypes, func(i *cloudprovider.InstanceType) bool {
+		return lo.ContainsBy(i.Offerings.Compatible(reqs).Available(), func(o cloudprovider.Offering) bool {
+			intensity, ok := c.carbonProvider.ZoneIntensity(o.Requirements.Get(v1.LabelTopologyZone).Any())
+			return ok && intensity <= float64(*policy.MaxIntensity)
+		})
+	})
+	return lo.Ternary(cleanerOfferingExists, CarbonDrift, ""), nil
+}
+
+// hasExceededCarbonCeiling tracks when the intensity of a zone rose above a carbon ceiling and returns true once it
+// has stayed above it for at least the given duration. Once above the ceiling, the zone has to fall below the
+// hysteresis band under the ceiling before it's considered clean again.
+func (c *CloudProvider) hasExceededCarbonCeiling(zone string, ceiling int64, duration time.Duration) bool {
+	intensity, ok := c.carbonProvider.ZoneIntensity(zone)
+	if !ok {
+		return false
+	}
+	key := fmt.Sprintf("%s/%d", zone, ceiling)
+	c.muCarbonDrift.Lock()
+	defer c.muCarbonDrift.Unlock()
+	since, exceeded := c.carbonCeilingExceeded[key]
+	switch {
+	case intensity > float64(ceiling):
+		if !exceeded {
+			since = c.clk.Now()
+			c.carbonCeilingExceeded[key] = since
+		}
+	case intensity < float64(ceiling)*(1-carbonDriftHysteresis):
+		delete(c.carbonCeilingExceeded, key)
+		return false
+	case !exceeded:
+		return false
+	}
+	return c.clk.Since(since) >= duration
+}
+
 func (c *CloudProvider) areStaticFieldsDrifted(nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass) cloudprovider.DriftReason {
 	nodeClassHash, foundNodeClassHash := nodeClass.Annotations[v1beta1.AnnotationEC2NodeClassHash]
 	nodeClassHashVersion, foundNodeClassHashVersion := nodeClass.Annotations[v1beta1.AnnotationEC2NodeClassHashVersion]
@@ -141,7 +217,11 @@ func (c *CloudProvider) getInstance(ctx context.Context, providerID string) (*in
 	if err != nil {
 		return nil, err
 	}
-	instance, err := c.instanceProvider.Get(ctx, instanceID)
+	instanceProvider, err := c.instanceProviderForProviderID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := instanceProvider.Get(ctx, instanceID)
 	if err != nil {
 		return nil, fmt.Errorf("getting instance, %w", err)
 	}
@@ -15,10 +15,15 @@ limitations under the License.
 package events
 
 import (
+	"fmt"
+	"time"
+
 	v1 "k8s.io/api/core/v1"
 
 	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	"sigs.k8s.io/karpenter/pkg/events"
+
+	awsv1beta1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 )
 
 func NodePoolFailedToResolveNodeClass(nodePool *v1beta1.NodePool) events.Event {
@@ -38,3 +43,35 @@ func NodeClaimFailedToResolveNodeClass(nodeClaim *v1beta1.NodeClaim) events.Even
 		DedupeValues:   []string{string(nodeClaim.UID)},
 	}
 }
+
+func NodeClassCarbonCeilingExceeded(nodeClass *awsv1beta1.EC2NodeClass, maxIntensity int64) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClass,
+		Type:           v1.EventTypeWarning,
+		Reason:         "CarbonCeilingExceeded",
+		Message:        fmt.Sprintf("No offerings are available below the maximum carbon intensity of %d gCO2e/kWh", maxIntensity),
+		DedupeValues:   []string{string(nodeClass.UID)},
+	}
+}
+
+func NodeClaimLaunchDeferred(nodeClaim *v1beta1.NodeClaim, currentIntensity, forecastIntensity float64, at time.Time) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClaim,
+		Type:           v1.EventTypeNormal,
+		Reason:         "LaunchDeferred",
+		Message: fmt.Sprintf("Deferring launch until %s when grid carbon intensity is forecast to fall from %.0f to %.0f gCO2e/kWh",
+			at.Format(time.RFC3339), currentIntensity, forecastIntensity),
+		DedupeValues: []string{string(nodeClaim.UID)},
+	}
+}
+
+func NodeClaimCarbonBudgetExhausted(nodeClaim *v1beta1.NodeClaim, budget float64) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClaim,
+		Type:           v1.EventTypeWarning,
+		Reason:         "CarbonBudgetExhausted",
+		Message: fmt.Sprintf("Refusing to launch, the carbon budget of %.2f kgCO2e for nodepool %s is exhausted and the nodeclaim is not carbon-critical",
+			budget, nodeClaim.Labels[v1beta1.NodePoolLabelKey]),
+		DedupeValues: []string{string(nodeClaim.UID)},
+	}
+}
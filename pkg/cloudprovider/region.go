@@ -0,0 +1,223 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
+	"github.com/aws/karpenter-provider-aws/pkg/utils"
+)
+
+// offeringLabels are the labels of instance type requirements that are derived from the instance type's offerings, so
+// they span the values of each region when the instance types of several regions are combined
+var offeringLabels = []string{
+	v1.LabelTopologyRegion,
+	v1.LabelTopologyZone,
+	v1beta1.LabelTopologyZoneID,
+	v1beta1.LabelTopologyZoneCarbonTier,
+	corev1beta1.CapacityTypeLabelKey,
+}
+
+// listInstanceTypes returns the instance types of an EC2NodeClass, with offerings in the cluster's region and in each
+// of the EC2NodeClass's resolved additional regions. A region whose instance types can't be listed is left out, so that
+// the other regions can still be launched into.
+func (c *CloudProvider) listInstanceTypes(ctx context.Context, kc *corev1beta1.KubeletConfiguration, nodeClass *v1beta1.EC2NodeClass) ([]*cloudprovider.InstanceType, error) {
+	instanceTypes, err := c.instanceTypeProvider.List(ctx, kc, nodeClass)
+	if err != nil {
+		return nil, err
+	}
+	for _, additionalRegion := range nodeClass.Spec.AdditionalRegions {
+		regionalNodeClass, ok := region.NodeClass(nodeClass, additionalRegion.Name)
+		if !ok {
+			continue
+		}
+		providers, err := c.regionProvider.Get(ctx, additionalRegion.Name)
+		if err != nil {
+			log.FromContext(ctx).WithValues("region", additionalRegion.Name).Error(err, "failed getting providers for additional region")
+			continue
+		}
+		regionalInstanceTypes, err := providers.InstanceTypeProvider.List(ctx, kc, regionalNodeClass)
+		if err != nil {
+			log.FromContext(ctx).WithValues("region", additionalRegion.Name).Error(err, "failed listing instance types for additional region")
+			continue
+		}
+		instanceTypes = mergeInstanceTypes(instanceTypes, regionalInstanceTypes)
+	}
+	return instanceTypes, nil
+}
+
+// mergeInstanceTypes combines instance types from different regions. Instance types offered in both are combined into
+// a new instance type with the offerings of both, since the instance types returned by the instance type provider are
+// cached and mustn't be modified.
+func mergeInstanceTypes(instanceTypes, regionalInstanceTypes []*cloudprovider.InstanceType) []*cloudprovider.InstanceType {
+	regional := lo.SliceToMap(regionalInstanceTypes, func(i *cloudprovider.InstanceType) (string, *cloudprovider.InstanceType) { return i.Name, i })
+	merged := make([]*cloudprovider.InstanceType, 0, len(instanceTypes)+len(regionalInstanceTypes))
+	for _, instanceType := range instanceTypes {
+		regionalInstanceType, ok := regional[instanceType.Name]
+		if !ok {
+			merged = append(merged, instanceType)
+			continue
+		}
+		delete(regional, instanceType.Name)
+		requirements := scheduling.NewRequirements(instanceType.Requirements.Values()...)
+		for _, key := range offeringLabels {
+			values := lo.Union(requirementValues(instanceType.Requirements, key), requirementValues(regionalInstanceType.Requirements, key))
+			if len(values) != 0 {
+				requirements[key] = scheduling.NewRequirement(key, v1.NodeSelectorOpIn, values...)
+			}
+		}
+		merged = append(merged, &cloudprovider.InstanceType{
+			Name:         instanceType.Name,
+			Requirements: requirements,
+			Offerings:    append(append(cloudprovider.Offerings{}, instanceType.Offerings...), regionalInstanceType.Offerings...),
+			Capacity:     instanceType.Capacity,
+			Overhead:     instanceType.Overhead,
+		})
+	}
+	// Instance types that are only offered in the additional region keep their order
+	for _, regionalInstanceType := range regionalInstanceTypes {
+		if _, ok := regional[regionalInstanceType.Name]; ok {
+			merged = append(merged, regionalInstanceType)
+		}
+	}
+	return merged
+}
+
+func requirementValues(requirements scheduling.Requirements, key string) []string {
+	if !requirements.Has(key) || requirements.Get(key).Operator() != v1.NodeSelectorOpIn {
+		return nil
+	}
+	return requirements.Get(key).Values()
+}
+
+// launchRegion returns the instance provider of the region with the cheapest offering that a NodeClaim could launch,
+// including the cost of carbon when the carbon policy prices it, along with the EC2NodeClass and instance types to
+// launch in that region. The instance types only keep their offerings in that region, so that the instance provider of
+// the region chooses between them.
+func (c *CloudProvider) launchRegion(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass,
+	instanceTypes []*cloudprovider.InstanceType) (instance.Provider, *v1beta1.EC2NodeClass, []*cloudprovider.InstanceType, error) {
+	if len(nodeClass.Status.AdditionalRegions) == 0 {
+		return c.instanceProvider, nodeClass, instanceTypes, nil
+	}
+	reqs := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...)
+	var offerings cloudprovider.Offerings
+	for _, instanceType := range instanceTypes {
+		offerings = append(offerings, instanceType.Offerings.Available().Compatible(reqs)...)
+	}
+	if len(offerings) == 0 {
+		return c.instanceProvider, nodeClass, instanceTypes, nil
+	}
+	launchRegion := offerings.Cheapest().Requirements.Get(v1.LabelTopologyRegion).Any()
+	if launchRegion == c.regionProvider.Region() {
+		return c.instanceProvider, nodeClass, regionalInstanceTypes(instanceTypes, launchRegion), nil
+	}
+	regionalNodeClass, ok := region.NodeClass(nodeClass, launchRegion)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("region %s of the cheapest offering isn't resolved", launchRegion)
+	}
+	providers, err := c.regionProvider.Get(ctx, launchRegion)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("getting providers for region %s, %w", launchRegion, err)
+	}
+	return providers.InstanceProvider, regionalNodeClass, regionalInstanceTypes(instanceTypes, launchRegion), nil
+}
+
+// regionalInstanceTypes returns the instance types that are offered in a region, with only their offerings in that
+// region
+func regionalInstanceTypes(instanceTypes []*cloudprovider.InstanceType, launchRegion string) []*cloudprovider.InstanceType {
+	inRegion := scheduling.NewRequirements(scheduling.NewRequirement(v1.LabelTopologyRegion, v1.NodeSelectorOpIn, launchRegion))
+	return lo.FilterMap(instanceTypes, func(instanceType *cloudprovider.InstanceType, _ int) (*cloudprovider.InstanceType, bool) {
+		offerings := instanceType.Offerings.Compatible(inRegion)
+		if len(offerings) == 0 {
+			return nil, false
+		}
+		requirements := scheduling.NewRequirements(instanceType.Requirements.Values()...)
+		for _, key := range offeringLabels {
+			values := lo.Uniq(lo.FilterMap(offerings.Available(), func(o cloudprovider.Offering, _ int) (string, bool) {
+				return o.Requirements.Get(key).Any(), o.Requirements.Has(key)
+			}))
+			if len(values) != 0 {
+				requirements[key] = scheduling.NewRequirement(key, v1.NodeSelectorOpIn, values...)
+			}
+		}
+		return &cloudprovider.InstanceType{
+			Name:         instanceType.Name,
+			Requirements: requirements,
+			Offerings:    offerings,
+			Capacity:     instanceType.Capacity,
+			Overhead:     instanceType.Overhead,
+		}, true
+	})
+}
+
+// additionalRegions returns the regions, other than the cluster's region, that instances may have been launched into.
+// These are the additional regions of every EC2NodeClass and of every NodeClaim, since a region may have been removed
+// from its EC2NodeClass while instances are still running in it, as well as every region that's already in use. Every
+// one of them must be listed, since the NodeClaims of instances that aren't listed are garbage collected.
+func (c *CloudProvider) additionalRegions(ctx context.Context) ([]string, error) {
+	nodeClassList := &v1beta1.EC2NodeClassList{}
+	if err := c.kubeClient.List(ctx, nodeClassList); err != nil {
+		return nil, fmt.Errorf("listing nodeclasses, %w", err)
+	}
+	nodeClaimList := &corev1beta1.NodeClaimList{}
+	if err := c.kubeClient.List(ctx, nodeClaimList); err != nil {
+		return nil, fmt.Errorf("listing nodeclaims, %w", err)
+	}
+	var regions []string
+	for _, nodeClass := range nodeClassList.Items {
+		regions = append(regions, lo.Map(nodeClass.Spec.AdditionalRegions, func(r v1beta1.AdditionalRegion, _ int) string { return r.Name })...)
+	}
+	for _, nodeClaim := range nodeClaimList.Items {
+		if zone, err := utils.ParseZone(nodeClaim.Status.ProviderID); err == nil {
+			if regionName, ok := utils.RegionForZone(zone); ok {
+				regions = append(regions, regionName)
+			}
+		}
+	}
+	regions = append(regions, lo.Map(c.regionProvider.List(), func(p *region.Providers, _ int) string { return p.Region })...)
+	regions = lo.Without(lo.Uniq(regions), c.regionProvider.Region())
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// instanceProviderForProviderID returns the instance provider for the region of the zone in a provider ID
+func (c *CloudProvider) instanceProviderForProviderID(ctx context.Context, providerID string) (instance.Provider, error) {
+	zone, err := utils.ParseZone(providerID)
+	if err != nil {
+		return nil, fmt.Errorf("getting zone, %w", err)
+	}
+	regionName, ok := utils.RegionForZone(zone)
+	if !ok || regionName == c.regionProvider.Region() {
+		return c.instanceProvider, nil
+	}
+	providers, err := c.regionProvider.Get(ctx, regionName)
+	if err != nil {
+		return nil, fmt.Errorf("getting providers for region %s, %w", regionName, err)
+	}
+	return providers.InstanceProvider, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -26,7 +27,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/client-go/tools/record"
 	clock "k8s.io/utils/clock/testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -42,13 +42,13 @@ import (
 	"github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclass/status"
 	"github.com/aws/karpenter-provider-aws/pkg/fake"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	corecloudproivder "sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/controllers/provisioning"
 	"sigs.k8s.io/karpenter/pkg/controllers/state"
-	"sigs.k8s.io/karpenter/pkg/events"
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/operator/scheme"
 	coretest "sigs.k8s.io/karpenter/pkg/test"
@@ -67,7 +67,7 @@ var prov *provisioning.Provisioner
 var cloudProvider *cloudprovider.CloudProvider
 var cluster *state.Cluster
 var fakeClock *clock.FakeClock
-var recorder events.Recorder
+var recorder *coretest.EventRecorder
 
 func TestAWS(t *testing.T) {
 	ctx = TestContextWithLogger(t)
@@ -82,9 +82,9 @@ var _ = BeforeSuite(func() {
 	ctx, stop = context.WithCancel(ctx)
 	awsEnv = test.NewEnvironment(ctx, env)
 	fakeClock = clock.NewFakeClock(time.Now())
-	recorder = events.NewRecorder(&record.FakeRecorder{})
+	recorder = coretest.NewEventRecorder()
 	cloudProvider = cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, recorder,
-		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider)
+		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.CarbonProvider, awsEnv.RegionProvider, fakeClock)
 	cluster = state.NewCluster(fakeClock, env.Client, cloudProvider)
 	prov = provisioning.NewProvisioner(env.Client, recorder, cloudProvider, cluster)
 })
@@ -100,6 +100,7 @@ var _ = BeforeEach(func() {
 
 	cluster.Reset()
 	awsEnv.Reset()
+	recorder.Reset()
 
 	awsEnv.LaunchTemplateProvider.KubeDNSIP = net.ParseIP("10.0.100.10")
 	awsEnv.LaunchTemplateProvider.ClusterEndpoint = "https://test-cluster"
@@ -226,6 +227,17 @@ var _ = Describe("CloudProvider", func() {
 		Expect(ok).To(BeTrue())
 		Expect(zoneID).To(Equal(subnet.ZoneID))
 	})
+	It("should return the carbon tier of the launched zone as a label on the nodeClaim", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+		cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cloudProviderNodeClaim).ToNot(BeNil())
+		intensity, ok := awsEnv.CarbonProvider.ZoneIntensity(cloudProviderNodeClaim.Labels[v1.LabelTopologyZone])
+		Expect(ok).To(BeTrue())
+		Expect(cloudProviderNodeClaim.Labels).To(HaveKeyWithValue(v1beta1.LabelTopologyZoneCarbonTier, carbon.Tier(intensity)))
+		Expect(cloudProviderNodeClaim.Labels).To(HaveKey(v1beta1.LabelInstanceWattsMax))
+		Expect(cloudProviderNodeClaim.Labels).To(HaveKey(v1beta1.LabelInstanceEmbodiedKgCO2e))
+	})
 	It("should return NodeClass Hash on the nodeClaim", func() {
 		ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
 		cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
@@ -243,6 +255,343 @@ var _ = Describe("CloudProvider", func() {
 		Expect(ok).To(BeTrue())
 		Expect(v).To(Equal(v1beta1.EC2NodeClassHashVersion))
 	})
+	It("should annotate the nodeclaim with the carbon details of its launch", func() {
+		nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+		cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cloudProviderNodeClaim.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationCarbonMode, string(v1beta1.CarbonModeWeighted)))
+		intensity, ok := awsEnv.CarbonProvider.ZoneIntensity(cloudProviderNodeClaim.Labels[v1.LabelTopologyZone])
+		Expect(ok).To(BeTrue())
+		Expect(cloudProviderNodeClaim.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationCarbonIntensityAtLaunch, fmt.Sprintf("%.0f", intensity)))
+		Expect(cloudProviderNodeClaim.Annotations).To(HaveKey(v1beta1.AnnotationEstimatedWatts))
+		watts, err := strconv.ParseFloat(cloudProviderNodeClaim.Annotations[v1beta1.AnnotationEstimatedWatts], 64)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(watts).To(BeNumerically(">", 0))
+	})
+	DescribeTable("should not annotate the nodeclaim with carbon details when the nodeclass isn't carbon-aware",
+		func(carbonPolicy *v1beta1.CarbonPolicy) {
+			nodeClass.Spec.CarbonPolicy = carbonPolicy
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim.Annotations).ToNot(HaveKey(v1beta1.AnnotationCarbonMode))
+			Expect(cloudProviderNodeClaim.Annotations).ToNot(HaveKey(v1beta1.AnnotationCarbonIntensityAtLaunch))
+			Expect(cloudProviderNodeClaim.Annotations).ToNot(HaveKey(v1beta1.AnnotationEstimatedWatts))
+		},
+		Entry("without a carbon policy", nil),
+		Entry("with the Off carbon mode", &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeOff}),
+	)
+	Context("Carbon Ceiling", func() {
+		It("should publish an event when the carbon ceiling leaves no offerings available", func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](1)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			for _, it := range instanceTypes {
+				Expect(it.Offerings.Available()).To(BeEmpty())
+			}
+			Expect(recorder.Calls("CarbonCeilingExceeded")).To(Equal(1))
+		})
+		It("should not publish an event when offerings are below the carbon ceiling", func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](10_000)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			_, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Calls("CarbonCeilingExceeded")).To(Equal(0))
+		})
+		It("should fail to launch when the carbon ceiling leaves no offerings available", func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](1)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(corecloudproivder.IsInsufficientCapacityError(err)).To(BeTrue())
+			Expect(cloudProviderNodeClaim).To(BeNil())
+		})
+	})
+	Context("Carbon Budget", func() {
+		BeforeEach(func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100), MaxIntensity: lo.ToPtr[int64](1)}
+			periodStart := (&carbon.Budget{Period: carbon.BudgetPeriodQuarterly}).CurrentPeriodStart(fakeClock.Now())
+			nodePool.Annotations = map[string]string{
+				v1beta1.AnnotationCarbonBudget:            "10",
+				v1beta1.AnnotationCarbonBudgetPeriodStart: periodStart.Format(time.RFC3339),
+				v1beta1.AnnotationCarbonBudgetConsumed:    "10.000",
+			}
+		})
+		It("should launch in the EC2NodeClass's carbon mode while the budget isn't exhausted", func() {
+			nodePool.Annotations[v1beta1.AnnotationCarbonBudgetConsumed] = "5.000"
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim).ToNot(BeNil())
+		})
+		It("should launch in the EC2NodeClass's carbon mode once the budget period has ended", func() {
+			nodePool.Annotations[v1beta1.AnnotationCarbonBudgetPeriodStart] = (&carbon.Budget{Period: carbon.BudgetPeriodQuarterly}).
+				CurrentPeriodStart(fakeClock.Now()).AddDate(0, -3, 0).Format(time.RFC3339)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim).ToNot(BeNil())
+		})
+		It("should escalate to Strict carbon mode once the budget is exhausted", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(corecloudproivder.IsInsufficientCapacityError(err)).To(BeTrue())
+			Expect(cloudProviderNodeClaim).To(BeNil())
+
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			for _, it := range instanceTypes {
+				Expect(it.Offerings.Available()).To(BeEmpty())
+			}
+		})
+		It("should not escalate when the EC2NodeClass has no maximum intensity", func() {
+			nodeClass.Spec.CarbonPolicy.MaxIntensity = nil
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim).ToNot(BeNil())
+		})
+		It("should refuse non-critical launches once the budget is exhausted", func() {
+			nodePool.Annotations[v1beta1.AnnotationCarbonBudgetAction] = string(carbon.BudgetActionRefuseNonCritical)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).To(HaveOccurred())
+			Expect(corecloudproivder.IsInsufficientCapacityError(err)).To(BeFalse())
+			Expect(cloudProviderNodeClaim).To(BeNil())
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(0))
+			Expect(recorder.Calls("CarbonBudgetExhausted")).To(Equal(1))
+		})
+		It("should launch critical nodeclaims once the budget is exhausted", func() {
+			nodePool.Annotations[v1beta1.AnnotationCarbonBudgetAction] = string(carbon.BudgetActionRefuseNonCritical)
+			nodeClaim.Spec.Requirements = append(nodeClaim.Spec.Requirements, corev1beta1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{
+					Key:      v1beta1.LabelCarbonCritical,
+					Operator: v1.NodeSelectorOpIn,
+					Values:   []string{"true"},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim).ToNot(BeNil())
+			Expect(recorder.Calls("CarbonBudgetExhausted")).To(Equal(0))
+		})
+	})
+	Context("Carbon Deferral", func() {
+		setForecast := func(forecast []carbon.Forecast) {
+			for _, zone := range []string{"test-zone-1a", "test-zone-1b", "test-zone-1c"} {
+				awsEnv.CarbonProvider.SetZoneIntensity(zone, 300)
+				awsEnv.CarbonProvider.SetForecast(zone, forecast)
+			}
+		}
+		BeforeEach(func() {
+			// The maximum deferral is measured from the nodeclaim's creation by the API server
+			fakeClock.SetTime(time.Now())
+			setForecast([]carbon.Forecast{
+				{CarbonIntensity: 280, Datetime: fakeClock.Now().Add(time.Hour)},
+				{CarbonIntensity: 100, Datetime: fakeClock.Now().Add(2 * time.Hour)},
+			})
+		})
+		It("should defer launches for delay tolerant NodePools when the forecast is much lower", func() {
+			nodePool.Annotations = map[string]string{v1beta1.AnnotationCarbonMaxDeferral: "4h"}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).To(HaveOccurred())
+			Expect(corecloudproivder.IsInsufficientCapacityError(err)).To(BeFalse())
+			Expect(cloudProviderNodeClaim).To(BeNil())
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(0))
+			Expect(recorder.Calls("LaunchDeferred")).To(Equal(1))
+		})
+		It("should not defer launches for NodePools that aren't delay tolerant", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim).ToNot(BeNil())
+			Expect(recorder.Calls("LaunchDeferred")).To(Equal(0))
+		})
+		It("should not defer launches when the much lower intensity is beyond the maximum deferral", func() {
+			nodePool.Annotations = map[string]string{v1beta1.AnnotationCarbonMaxDeferral: "90m"}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim).ToNot(BeNil())
+			Expect(recorder.Calls("LaunchDeferred")).To(Equal(0))
+		})
+		It("should not defer launches when the forecast is only marginally lower", func() {
+			setForecast([]carbon.Forecast{{CarbonIntensity: 280, Datetime: fakeClock.Now().Add(time.Hour)}})
+			nodePool.Annotations = map[string]string{v1beta1.AnnotationCarbonMaxDeferral: "4h"}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim).ToNot(BeNil())
+		})
+		It("should not defer launches beyond the maximum deferral since the nodeclaim was created", func() {
+			nodePool.Annotations = map[string]string{v1beta1.AnnotationCarbonMaxDeferral: "4h"}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			fakeClock.SetTime(nodeClaim.CreationTimestamp.Add(2 * time.Hour))
+			// The much lower intensity is within the maximum deferral from now, but not from the nodeclaim's creation
+			setForecast([]carbon.Forecast{{CarbonIntensity: 100, Datetime: nodeClaim.CreationTimestamp.Add(5 * time.Hour)}})
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim).ToNot(BeNil())
+			Expect(recorder.Calls("LaunchDeferred")).To(Equal(0))
+		})
+		It("should not defer launches when the maximum deferral is invalid", func() {
+			nodePool.Annotations = map[string]string{v1beta1.AnnotationCarbonMaxDeferral: "a while"}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim).ToNot(BeNil())
+		})
+	})
+	Context("Additional Regions", func() {
+		BeforeEach(func() {
+			nodeClass.Spec.AdditionalRegions = []v1beta1.AdditionalRegion{
+				{
+					Name:                       "us-east-1",
+					SubnetSelectorTerms:        []v1beta1.SubnetSelectorTerm{{Tags: map[string]string{"*": "*"}}},
+					SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{{Tags: map[string]string{"*": "*"}}},
+				},
+			}
+			nodeClass.Status.AdditionalRegions = []v1beta1.AdditionalRegionStatus{
+				{
+					Name:           "us-east-1",
+					Subnets:        []v1beta1.Subnet{{ID: "subnet-east1", Zone: "us-east-1a", ZoneID: "use1-az1"}},
+					SecurityGroups: []v1beta1.SecurityGroup{{ID: "sg-east1", Name: "securityGroup-east1"}},
+					AMIs:           nodeClass.Status.AMIs,
+				},
+			}
+			awsEnv.RegionalEC2API.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{
+				{SubnetId: aws.String("subnet-east1"), AvailabilityZone: aws.String("us-east-1a"), AvailabilityZoneId: aws.String("use1-az1"), AvailableIpAddressCount: aws.Int64(100),
+					Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-subnet-east1")}}},
+			}})
+			awsEnv.RegionalEC2API.DescribeInstanceTypeOfferingsOutput.Set(&ec2.DescribeInstanceTypeOfferingsOutput{InstanceTypeOfferings: []*ec2.InstanceTypeOffering{
+				{InstanceType: aws.String("m5.large"), Location: aws.String("us-east-1a")},
+			}})
+		})
+		It("should offer instance types in the cluster's region and in additional regions", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			instanceType, ok := lo.Find(instanceTypes, func(i *corecloudproivder.InstanceType) bool { return i.Name == "m5.large" })
+			Expect(ok).To(BeTrue())
+			Expect(instanceType.Requirements.Get(v1.LabelTopologyRegion).Values()).To(ConsistOf(fake.DefaultRegion, "us-east-1"))
+			Expect(instanceType.Requirements.Get(v1.LabelTopologyZone).Has("us-east-1a")).To(BeTrue())
+			regional := lo.Filter(instanceType.Offerings, func(o corecloudproivder.Offering, _ int) bool {
+				return o.Requirements.Get(v1.LabelTopologyRegion).Any() == "us-east-1"
+			})
+			Expect(regional).ToNot(BeEmpty())
+			for _, offering := range regional {
+				Expect(offering.Requirements.Get(v1.LabelTopologyZone).Any()).To(Equal("us-east-1a"))
+			}
+		})
+		It("should only offer instance types in the cluster's region when the additional region isn't resolved", func() {
+			nodeClass.Status.AdditionalRegions = nil
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			for _, instanceType := range instanceTypes {
+				Expect(instanceType.Requirements.Get(v1.LabelTopologyRegion).Values()).To(ConsistOf(fake.DefaultRegion))
+			}
+		})
+		It("should launch into an additional region through the region's EC2 API", func() {
+			nodeClaim.Spec.Requirements = append(nodeClaim.Spec.Requirements, corev1beta1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1.LabelTopologyRegion, Operator: v1.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(0))
+			Expect(awsEnv.RegionalEC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			createFleetInput := awsEnv.RegionalEC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			for _, override := range createFleetInput.LaunchTemplateConfigs[0].Overrides {
+				Expect(aws.StringValue(override.SubnetId)).To(Equal("subnet-east1"))
+			}
+			Expect(cloudProviderNodeClaim.Labels).To(HaveKeyWithValue(v1.LabelTopologyRegion, "us-east-1"))
+			Expect(cloudProviderNodeClaim.Labels).To(HaveKeyWithValue(v1.LabelTopologyZone, "us-east-1a"))
+			Expect(cloudProviderNodeClaim.Labels).To(HaveKeyWithValue(v1beta1.LabelTopologyZoneID, "use1-az1"))
+		})
+		It("should launch into the cluster's region when the NodeClaim requires it", func() {
+			nodeClaim.Spec.Requirements = append(nodeClaim.Spec.Requirements, corev1beta1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1.LabelTopologyRegion, Operator: v1.NodeSelectorOpIn, Values: []string{fake.DefaultRegion}},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			Expect(awsEnv.RegionalEC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(0))
+			Expect(cloudProviderNodeClaim.Labels).To(HaveKeyWithValue(v1.LabelTopologyRegion, fake.DefaultRegion))
+		})
+		It("should get and delete instances in an additional region through the region's EC2 API", func() {
+			nodeClaim.Spec.Requirements = append(nodeClaim.Spec.Requirements, corev1beta1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1.LabelTopologyRegion, Operator: v1.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cloudProviderNodeClaim.Status.ProviderID).To(ContainSubstring("us-east-1a"))
+
+			got, err := cloudProvider.Get(ctx, cloudProviderNodeClaim.Status.ProviderID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(got.Labels).To(HaveKeyWithValue(v1.LabelTopologyZone, "us-east-1a"))
+
+			nodeClaims, err := cloudProvider.List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lo.Map(nodeClaims, func(nc *corev1beta1.NodeClaim, _ int) string { return nc.Status.ProviderID })).To(ContainElement(cloudProviderNodeClaim.Status.ProviderID))
+
+			Expect(cloudProvider.Delete(ctx, cloudProviderNodeClaim)).To(Succeed())
+			Expect(awsEnv.RegionalEC2API.TerminateInstancesBehavior.CalledWithInput.Len()).To(Equal(1))
+			Expect(awsEnv.EC2API.TerminateInstancesBehavior.CalledWithInput.Len()).To(Equal(0))
+		})
+		It("should list instances in additional regions that haven't been used since starting", func() {
+			nodeClaim.Spec.Requirements = append(nodeClaim.Spec.Requirements, corev1beta1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1.LabelTopologyRegion, Operator: v1.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			awsEnv.RegionProvider.Reset()
+
+			nodeClaims, err := cloudProvider.List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lo.Map(nodeClaims, func(nc *corev1beta1.NodeClaim, _ int) string { return nc.Status.ProviderID })).To(ContainElement(cloudProviderNodeClaim.Status.ProviderID))
+		})
+		It("should list instances in regions that have been removed from the nodeclass", func() {
+			nodeClaim.Spec.Requirements = append(nodeClaim.Spec.Requirements, corev1beta1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1.LabelTopologyRegion, Operator: v1.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			nodeClaim.Status.ProviderID = cloudProviderNodeClaim.Status.ProviderID
+			nodeClass.Spec.AdditionalRegions = nil
+			nodeClass.Status.AdditionalRegions = nil
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			awsEnv.RegionProvider.Reset()
+
+			nodeClaims, err := cloudProvider.List(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lo.Map(nodeClaims, func(nc *corev1beta1.NodeClaim, _ int) string { return nc.Status.ProviderID })).To(ContainElement(cloudProviderNodeClaim.Status.ProviderID))
+		})
+		It("should drift nodes in regions that have been removed from the nodeclass", func() {
+			nodeClaim.Spec.Requirements = append(nodeClaim.Spec.Requirements, corev1beta1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1.LabelTopologyRegion, Operator: v1.NodeSelectorOpIn, Values: []string{"us-east-1"}},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+			cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			nodeClaim.Status.ProviderID = cloudProviderNodeClaim.Status.ProviderID
+			nodeClass.Spec.AdditionalRegions = nil
+			nodeClass.Status.AdditionalRegions = nil
+			ExpectApplied(ctx, env.Client, nodeClass)
+
+			isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isDrifted).To(Equal(cloudprovider.RegionDrift))
+		})
+	})
 	Context("EC2 Context", func() {
 		contextID := "context-1234"
 		It("should set context on the CreateFleet request if specified on the NodePool", func() {
@@ -1045,6 +1394,96 @@ var _ = Describe("CloudProvider", func() {
 				Expect(isDrifted).To(BeEmpty())
 			})
 		})
+		Context("Carbon Drift", func() {
+			var carbonCloudProvider *cloudprovider.CloudProvider
+			BeforeEach(func() {
+				// use a cloud provider per test so that time spent above the carbon ceiling isn't carried across tests
+				carbonCloudProvider = cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, recorder,
+					env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.CarbonProvider, awsEnv.RegionProvider, fakeClock)
+				awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1a", 500)
+				awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1b", 100)
+				nodeClass.Status.Subnets = []v1beta1.Subnet{
+					{ID: validSubnet1, Zone: "test-zone-1a"},
+					{ID: validSubnet2, Zone: "test-zone-1b"},
+				}
+				nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{
+					Mode:         v1beta1.CarbonModeWeighted,
+					CarbonPrice:  lo.ToPtr[int64](100),
+					MaxIntensity: lo.ToPtr[int64](300),
+					DriftAfter:   &metav1.Duration{Duration: time.Hour},
+				}
+				ExpectApplied(ctx, env.Client, nodeClass)
+				nodeClaim.Labels = lo.Assign(nodeClaim.Labels, map[string]string{v1.LabelTopologyZone: "test-zone-1a"})
+			})
+			It("should return drifted once the zone has stayed above the carbon ceiling for driftAfter", func() {
+				isDrifted, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+				fakeClock.Step(30 * time.Minute)
+				isDrifted, err = carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+				fakeClock.Step(30 * time.Minute)
+				isDrifted, err = carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(Equal(cloudprovider.CarbonDrift))
+			})
+			It("should not return drifted if the zone is below the carbon ceiling", func() {
+				nodeClaim.Labels[v1.LabelTopologyZone] = "test-zone-1b"
+				_, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				fakeClock.Step(2 * time.Hour)
+				isDrifted, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+			It("should not return drifted if there is no offering below the carbon ceiling", func() {
+				awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1b", 400)
+				_, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				fakeClock.Step(2 * time.Hour)
+				isDrifted, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+			It("should not return drifted if driftAfter is not set", func() {
+				nodeClass.Spec.CarbonPolicy.DriftAfter = nil
+				ExpectApplied(ctx, env.Client, nodeClass)
+				_, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				fakeClock.Step(2 * time.Hour)
+				isDrifted, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+			It("should keep counting time above the carbon ceiling while the zone is within the hysteresis band", func() {
+				_, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				fakeClock.Step(30 * time.Minute)
+				awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1a", 280)
+				_, err = carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				fakeClock.Step(30 * time.Minute)
+				isDrifted, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(Equal(cloudprovider.CarbonDrift))
+			})
+			It("should restart counting time above the carbon ceiling once the zone falls below the hysteresis band", func() {
+				_, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				fakeClock.Step(30 * time.Minute)
+				awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1a", 200)
+				_, err = carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1a", 500)
+				_, err = carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				fakeClock.Step(30 * time.Minute)
+				isDrifted, err := carbonCloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+		})
 	})
 	Context("Subnet Compatibility", func() {
 		// Note when debugging these tests -
@@ -1082,7 +1521,7 @@ var _ = Describe("CloudProvider", func() {
 				{SubnetId: aws.String("test-subnet-2"), AvailabilityZone: aws.String("test-zone-1a"), AvailabilityZoneId: aws.String("tstz1-1a"), AvailableIpAddressCount: aws.Int64(100),
 					Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-subnet-2")}}},
 			}})
-			controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+			controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider, awsEnv.RegionProvider, awsEnv.CarbonProvider)
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
 			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{v1.LabelTopologyZone: "test-zone-1a"}})
@@ -1099,7 +1538,7 @@ var _ = Describe("CloudProvider", func() {
 				{SubnetId: aws.String("test-subnet-2"), AvailabilityZone: aws.String("test-zone-1a"), AvailabilityZoneId: aws.String("tstz1-1a"), AvailableIpAddressCount: aws.Int64(11),
 					Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-subnet-2")}}},
 			}})
-			controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+			controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider, awsEnv.RegionProvider, awsEnv.CarbonProvider)
 			nodePool.Spec.Template.Spec.Kubelet = &corev1beta1.KubeletConfiguration{MaxPods: aws.Int32(1)}
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
 			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
@@ -1137,7 +1576,7 @@ var _ = Describe("CloudProvider", func() {
 			}})
 			nodeClass.Spec.SubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{{Tags: map[string]string{"Name": "test-subnet-1"}}}
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
-			controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+			controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider, awsEnv.RegionProvider, awsEnv.CarbonProvider)
 			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 			podSubnet1 := coretest.UnschedulablePod()
 			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, podSubnet1)
@@ -23,8 +23,12 @@ import (
 	nodeclasshash "github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclass/hash"
 	nodeclassstatus "github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclass/status"
 	nodeclasstermination "github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclass/termination"
+	controllerscarbon "github.com/aws/karpenter-provider-aws/pkg/controllers/providers/carbon"
+	controllerscommitment "github.com/aws/karpenter-provider-aws/pkg/controllers/providers/commitment"
 	controllersinstancetype "github.com/aws/karpenter-provider-aws/pkg/controllers/providers/instancetype"
 	controllerspricing "github.com/aws/karpenter-provider-aws/pkg/controllers/providers/pricing"
+	controllerspricingoverrides "github.com/aws/karpenter-provider-aws/pkg/controllers/providers/pricing/overrides"
+	controllersregion "github.com/aws/karpenter-provider-aws/pkg/controllers/providers/region"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
 
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -37,14 +41,18 @@ import (
 
 	"github.com/aws/karpenter-provider-aws/pkg/cache"
 	"github.com/aws/karpenter-provider-aws/pkg/controllers/interruption"
+	nodeclaimemissions "github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclaim/emissions"
 	nodeclaimgarbagecollection "github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclaim/garbagecollection"
 	nodeclaimtagging "github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclaim/tagging"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/commitment"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/sqs"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
@@ -53,16 +61,26 @@ import (
 func NewControllers(ctx context.Context, sess *session.Session, clk clock.Clock, kubeClient client.Client, recorder events.Recorder,
 	unavailableOfferings *cache.UnavailableOfferings, cloudProvider cloudprovider.CloudProvider, subnetProvider subnet.Provider,
 	securityGroupProvider securitygroup.Provider, instanceProfileProvider instanceprofile.Provider, instanceProvider instance.Provider,
-	pricingProvider pricing.Provider, amiProvider amifamily.Provider, launchTemplateProvider launchtemplate.Provider, instanceTypeProvider instancetype.Provider) []controller.Controller {
+	pricingProvider pricing.Provider, carbonProvider carbon.Provider, amiProvider amifamily.Provider, launchTemplateProvider launchtemplate.Provider, instanceTypeProvider instancetype.Provider,
+	regionProvider region.Provider, commitmentProvider commitment.Provider, pricingOverrides *pricing.Overrides) []controller.Controller {
 
+	emissionsController := nodeclaimemissions.NewController(kubeClient, clk, recorder, regionProvider)
 	controllers := []controller.Controller{
 		nodeclasshash.NewController(kubeClient),
-		nodeclassstatus.NewController(kubeClient, subnetProvider, securityGroupProvider, amiProvider, instanceProfileProvider, launchTemplateProvider),
-		nodeclasstermination.NewController(kubeClient, recorder, instanceProfileProvider, launchTemplateProvider),
+		nodeclassstatus.NewController(kubeClient, subnetProvider, securityGroupProvider, amiProvider, instanceProfileProvider, launchTemplateProvider, regionProvider, carbonProvider),
+		nodeclasstermination.NewController(kubeClient, recorder, instanceProfileProvider, regionProvider),
 		nodeclaimgarbagecollection.NewController(kubeClient, cloudProvider),
-		nodeclaimtagging.NewController(kubeClient, instanceProvider),
+		nodeclaimtagging.NewController(kubeClient, regionProvider),
+		emissionsController,
+		nodeclaimemissions.NewTerminationController(kubeClient, emissionsController),
 		controllerspricing.NewController(pricingProvider),
+		controllerscarbon.NewController(carbonProvider),
+		controllerscommitment.NewController(kubeClient, commitmentProvider),
 		controllersinstancetype.NewController(instanceTypeProvider),
+		controllersregion.NewController(regionProvider),
+	}
+	if options.FromContext(ctx).PricingOverridesConfigMap != "" {
+		controllers = append(controllers, controllerspricingoverrides.NewController(kubeClient, pricingOverrides))
 	}
 	if options.FromContext(ctx).InterruptionQueue != "" {
 		sqsapi := servicesqs.New(sess)
@@ -0,0 +1,122 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emissions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	"go.uber.org/multierr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/metrics"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+)
+
+// consumeBudgets consumes the emissions accounted for in this pass from the carbon budgets of their NodePools, and
+// returns the NodePools whose budgets they couldn't be consumed from. Budgets are consumed before the NodeClaims are
+// patched, so emissions may be consumed twice if a NodeClaim fails to be patched, but they're never missing from a
+// budget.
+func (c *Controller) consumeBudgets(ctx context.Context, nodePoolGrams map[string]float64, now time.Time) (sets.Set[string], error) {
+	nodePoolList := &corev1beta1.NodePoolList{}
+	if err := c.kubeClient.List(ctx, nodePoolList); err != nil {
+		return sets.KeySet(nodePoolGrams), fmt.Errorf("listing nodepools, %w", err)
+	}
+	budgeted := sets.New[string]()
+	failed := sets.New[string]()
+	var errs error
+	for i := range nodePoolList.Items {
+		nodePool := &nodePoolList.Items[i]
+		budget, err := carbon.NodePoolBudget(nodePool)
+		if err != nil {
+			log.FromContext(ctx).WithValues("nodepool", nodePool.Name).Error(err, "failed parsing carbon budget")
+			continue
+		}
+		if budget == nil {
+			continue
+		}
+		budgeted.Insert(nodePool.Name)
+		if err := c.consumeBudget(ctx, nodePool, budget, nodePoolGrams[nodePool.Name], now); err != nil {
+			if errors.IsConflict(err) {
+				err = c.consumeNodePoolBudget(ctx, nodePool.Name, nodePoolGrams[nodePool.Name], now)
+			}
+			if err != nil {
+				failed.Insert(nodePool.Name)
+				errs = multierr.Append(errs, fmt.Errorf("consuming carbon budget of nodepool %s, %w", nodePool.Name, err))
+			}
+		}
+	}
+	for name := range c.budgeted.Difference(budgeted) {
+		nodePoolCarbonBudgetConsumed.Delete(prometheus.Labels{metrics.NodePoolLabel: name})
+		nodePoolCarbonBudgetRemaining.Delete(prometheus.Labels{metrics.NodePoolLabel: name})
+	}
+	c.budgeted = budgeted
+	return failed, errs
+}
+
+// consumeNodePoolBudget consumes grams from the carbon budget of a NodePool, if it has one. The NodePool is read afresh
+// on conflicts, since budgets are consumed both by the periodic accounting and as NodeClaims are settled.
+func (c *Controller) consumeNodePoolBudget(ctx context.Context, name string, grams float64, now time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nodePool := &corev1beta1.NodePool{}
+		if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: name}, nodePool); err != nil {
+			return client.IgnoreNotFound(fmt.Errorf("getting nodepool, %w", err))
+		}
+		budget, err := carbon.NodePoolBudget(nodePool)
+		if err != nil || budget == nil {
+			return nil
+		}
+		return c.consumeBudget(ctx, nodePool, budget, grams, now)
+	})
+}
+
+// consumeBudget adds grams to the emissions consumed from a NodePool's budget, starting afresh when a new budget period
+// has begun, and persists the consumed and remaining totals in annotations on the NodePool
+func (c *Controller) consumeBudget(ctx context.Context, nodePool *corev1beta1.NodePool, budget *carbon.Budget, grams float64, now time.Time) error {
+	exhausted := budget.Exhausted(now)
+	budget.ConsumedKgCO2e = budget.Consumed(now) + grams/1000
+	budget.PeriodStart = budget.CurrentPeriodStart(now)
+
+	stored := nodePool.DeepCopy()
+	nodePool.Annotations = lo.Assign(nodePool.Annotations, map[string]string{
+		v1beta1.AnnotationCarbonBudgetPeriodStart: budget.PeriodStart.Format(time.RFC3339),
+		v1beta1.AnnotationCarbonBudgetConsumed:    strconv.FormatFloat(budget.ConsumedKgCO2e, 'f', 3, 64),
+		v1beta1.AnnotationCarbonBudgetRemaining:   strconv.FormatFloat(budget.Remaining(now), 'f', 3, 64),
+	})
+	if !equality.Semantic.DeepEqual(stored.Annotations, nodePool.Annotations) {
+		if err := c.kubeClient.Patch(ctx, nodePool, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+			return client.IgnoreNotFound(fmt.Errorf("patching nodepool, %w", err))
+		}
+	}
+	nodePoolCarbonBudgetConsumed.With(prometheus.Labels{metrics.NodePoolLabel: nodePool.Name}).Set(budget.ConsumedKgCO2e)
+	nodePoolCarbonBudgetRemaining.With(prometheus.Labels{metrics.NodePoolLabel: nodePool.Name}).Set(budget.Remaining(now))
+	if !exhausted && budget.Exhausted(now) {
+		c.recorder.Publish(NodePoolCarbonBudgetExhausted(nodePool, budget))
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emissions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/events"
+	"sigs.k8s.io/karpenter/pkg/metrics"
+	"sigs.k8s.io/karpenter/pkg/operator/controller"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
+)
+
+// accountingPeriod is how often emissions are accounted for. Each period patches every launched NodeClaim, so this
+// trades the freshness of the running totals against load on the API server.
+const accountingPeriod = 5 * time.Minute
+
+// Controller periodically integrates the estimated emissions of every launched NodeClaim over its lifetime. Emissions
+// are estimated from the NodeClaim's instance type and the current grid intensity of its zone, so a NodeClaim's emissions
+// follow the intensity of the grid over its lifetime. The running total, and the time up until which it was accounted
+// for, are persisted in annotations on the NodeClaim so that totals survive controller restarts. Once a NodeClaim's
+// instance is terminated, the TerminationController settles and publishes its final total. Emissions are also consumed
+// from the carbon budgets of NodePools.
+type Controller struct {
+	kubeClient     client.Client
+	clock          clock.Clock
+	recorder       events.Recorder
+	regionProvider region.Provider
+
+	// budgeted holds the NodePools with carbon budgets so that their metrics can be removed once they no longer do
+	budgeted sets.Set[string]
+}
+
+func NewController(kubeClient client.Client, clk clock.Clock, recorder events.Recorder, regionProvider region.Provider) *Controller {
+	return &Controller{
+		kubeClient:     kubeClient,
+		clock:          clk,
+		recorder:       recorder,
+		regionProvider: regionProvider,
+		budgeted:       sets.New[string](),
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	nodeClaimList := &corev1beta1.NodeClaimList{}
+	if err := c.kubeClient.List(ctx, nodeClaimList); err != nil {
+		return reconcile.Result{}, fmt.Errorf("listing nodeclaims, %w", err)
+	}
+	nodeClasses := map[string]*v1beta1.EC2NodeClass{}
+	now := c.clock.Now()
+	accrued := map[*corev1beta1.NodeClaim]float64{}
+	nodePoolGrams := map[string]float64{}
+	for i := range nodeClaimList.Items {
+		nodeClaim := &nodeClaimList.Items[i]
+		// NodeClaims whose instance is terminated are settled by the TerminationController
+		if nodeClaim.Status.ProviderID == "" || settling(nodeClaim) {
+			continue
+		}
+		nodeClass, err := c.resolveNodeClass(ctx, nodeClaim, nodeClasses)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		grams, ok := c.accrue(ctx, nodeClaim, nodeClass, now)
+		if !ok {
+			continue
+		}
+		accrued[nodeClaim] = grams
+		if nodePoolName, ok := nodeClaim.Labels[corev1beta1.NodePoolLabelKey]; ok {
+			nodePoolGrams[nodePoolName] += grams + unbudgeted(nodeClaim)
+		}
+	}
+	// Budgets are consumed before the NodeClaims are advanced, and the emissions of NodePools whose budgets couldn't be
+	// consumed are kept on their NodeClaims until they are, so that budgets are never under-counted
+	failed, budgetErr := c.consumeBudgets(ctx, nodePoolGrams, now)
+	for nodeClaim, grams := range accrued {
+		pending := 0.0
+		if failed.Has(nodeClaim.Labels[corev1beta1.NodePoolLabelKey]) {
+			pending = grams + unbudgeted(nodeClaim)
+		}
+		stored := nodeClaim.DeepCopy()
+		accountFor(nodeClaim, grams, pending, now)
+		// The optimistic lock prevents a period from being accounted for twice when the NodeClaim is settled
+		// concurrently. NodeClaims that changed since they were listed are accounted for in the next pass.
+		if err := c.kubeClient.Patch(ctx, nodeClaim, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+			if errors.IsConflict(err) || errors.IsNotFound(err) {
+				continue
+			}
+			return reconcile.Result{}, fmt.Errorf("patching nodeclaim, %w", err)
+		}
+		observe(ctx, nodeClaim, grams)
+	}
+	if budgetErr != nil {
+		return reconcile.Result{}, budgetErr
+	}
+	return reconcile.Result{RequeueAfter: accountingPeriod}, nil
+}
+
+// accrue returns the emissions of a NodeClaim since it was last accounted for. It returns false if they can't be
+// estimated yet, in which case the NodeClaim is left as is so that the whole period is accounted for once they can.
+func (c *Controller) accrue(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass, now time.Time) (float64, bool) {
+	// Emissions are estimated by the instance types of the region that the NodeClaim was launched in
+	providers, err := c.regionProvider.ForZone(ctx, nodeClaim.Labels[v1.LabelTopologyZone])
+	if err != nil {
+		log.FromContext(ctx).WithValues("nodeclaim", nodeClaim.Name).Error(err, "failed getting providers for region")
+		return 0, false
+	}
+	gramsPerHour, ok := providers.InstanceTypeProvider.EstimatedEmissions(nodeClaim.Labels[v1.LabelInstanceTypeStable], nodeClaim.Labels[v1.LabelTopologyZone],
+		nodeClass != nil && nodeClass.Spec.CarbonPolicy != nil && lo.FromPtr(nodeClass.Spec.CarbonPolicy.IncludeEmbodied))
+	if !ok {
+		return 0, false
+	}
+	accountedAt := accountedAt(nodeClaim)
+	if !now.After(accountedAt) {
+		return 0, false
+	}
+	return gramsPerHour * now.Sub(accountedAt).Hours(), true
+}
+
+// accountFor adds grams to the running total of a NodeClaim, advances the time up until which it's been accounted for
+// and records the emissions that are yet to be consumed from its NodePool's carbon budget
+func accountFor(nodeClaim *corev1beta1.NodeClaim, grams, pending float64, now time.Time) {
+	nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{
+		v1beta1.AnnotationEmissions:            strconv.FormatFloat(emissions(nodeClaim)+grams, 'f', 2, 64),
+		v1beta1.AnnotationEmissionsAccountedAt: now.UTC().Format(time.RFC3339),
+	})
+	if pending > 0 {
+		nodeClaim.Annotations[v1beta1.AnnotationEmissionsUnbudgeted] = strconv.FormatFloat(pending, 'f', 2, 64)
+	} else {
+		delete(nodeClaim.Annotations, v1beta1.AnnotationEmissionsUnbudgeted)
+	}
+}
+
+// observe records emissions that have been persisted on a NodeClaim
+func observe(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, grams float64) {
+	nodeCarbonEmissions.With(prometheus.Labels{
+		metrics.NodePoolLabel:     nodeClaim.Labels[corev1beta1.NodePoolLabelKey],
+		zoneLabel:                 nodeClaim.Labels[v1.LabelTopologyZone],
+		metrics.CapacityTypeLabel: nodeClaim.Labels[corev1beta1.CapacityTypeLabelKey],
+	}).Add(grams)
+	log.FromContext(ctx).WithValues("nodeclaim", nodeClaim.Name).V(1).Info("accounted for emissions", "gco2e", grams)
+}
+
+// accountedAt returns the time up until which a NodeClaim's emissions have been accounted for, which is its launch time
+// if they've never been accounted for
+func accountedAt(nodeClaim *corev1beta1.NodeClaim) time.Time {
+	if t, err := time.Parse(time.RFC3339, nodeClaim.Annotations[v1beta1.AnnotationEmissionsAccountedAt]); err == nil {
+		return t
+	}
+	if launched := nodeClaim.StatusConditions().Get(corev1beta1.ConditionTypeLaunched); launched.IsTrue() {
+		return launched.LastTransitionTime.Time
+	}
+	return nodeClaim.CreationTimestamp.Time
+}
+
+// emissions returns the running total of a NodeClaim's emissions, in gCO2e
+func emissions(nodeClaim *corev1beta1.NodeClaim) float64 {
+	grams, err := strconv.ParseFloat(nodeClaim.Annotations[v1beta1.AnnotationEmissions], 64)
+	if err != nil {
+		return 0
+	}
+	return grams
+}
+
+// unbudgeted returns the emissions of a NodeClaim, in gCO2e, that have been accounted for but are yet to be consumed
+// from its NodePool's carbon budget
+func unbudgeted(nodeClaim *corev1beta1.NodeClaim) float64 {
+	grams, err := strconv.ParseFloat(nodeClaim.Annotations[v1beta1.AnnotationEmissionsUnbudgeted], 64)
+	if err != nil {
+		return 0
+	}
+	return grams
+}
+
+// resolveNodeClass returns the EC2NodeClass of a NodeClaim, or nil if it no longer exists. Resolved EC2NodeClasses are
+// memoized for the duration of a reconcile since most NodeClaims share a handful of EC2NodeClasses.
+func (c *Controller) resolveNodeClass(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, nodeClasses map[string]*v1beta1.EC2NodeClass) (*v1beta1.EC2NodeClass, error) {
+	if nodeClaim.Spec.NodeClassRef == nil {
+		return nil, nil
+	}
+	name := nodeClaim.Spec.NodeClassRef.Name
+	if nodeClass, ok := nodeClasses[name]; ok {
+		return nodeClass, nil
+	}
+	nodeClass := &v1beta1.EC2NodeClass{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: name}, nodeClass); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("getting ec2nodeclass, %w", err)
+		}
+		nodeClass = nil
+	}
+	nodeClasses[name] = nodeClass
+	return nodeClass, nil
+}
+
+func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+	return controller.NewSingletonManagedBy(m).
+		Named("nodeclaim.emissions").
+		Complete(c)
+}
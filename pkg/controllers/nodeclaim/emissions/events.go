@@ -0,0 +1,48 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emissions
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/events"
+
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+)
+
+func NodeClaimEmissionsEvent(nodeClaim *corev1beta1.NodeClaim, grams float64) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClaim,
+		Type:           v1.EventTypeNormal,
+		Reason:         "EmissionsAccounted",
+		Message:        fmt.Sprintf("Emitted an estimated %.2f gCO2e over its lifetime in nodepool %s", grams, nodeClaim.Labels[corev1beta1.NodePoolLabelKey]),
+		DedupeValues:   []string{string(nodeClaim.UID)},
+	}
+}
+
+func NodePoolCarbonBudgetExhausted(nodePool *corev1beta1.NodePool, budget *carbon.Budget) events.Event {
+	return events.Event{
+		InvolvedObject: nodePool,
+		Type:           v1.EventTypeWarning,
+		Reason:         "CarbonBudgetExhausted",
+		Message: fmt.Sprintf("Consumed its %s carbon budget of %.2f kgCO2e, launches are restricted by the %s action until the period ends",
+			strings.ToLower(string(budget.Period)), budget.KgCO2e, budget.Action),
+		DedupeValues: []string{string(nodePool.UID)},
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emissions
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/karpenter/pkg/metrics"
+)
+
+const (
+	zoneLabel         = "zone"
+	nodePoolSubsystem = "nodepool"
+)
+
+var (
+	nodeCarbonEmissions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.NodeSubsystem,
+			Name:      "carbon_emissions_grams_total",
+			Help:      "Estimated emissions, in grams of CO2 equivalent, of nodes launched by Karpenter since the controller started, based on nodepool, zone, and capacity type.",
+		},
+		[]string{
+			metrics.NodePoolLabel,
+			zoneLabel,
+			metrics.CapacityTypeLabel,
+		},
+	)
+	nodePoolCarbonBudgetConsumed = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: nodePoolSubsystem,
+			Name:      "carbon_budget_consumed_kgco2e",
+			Help:      "Estimated emissions, in kilograms of CO2 equivalent, consumed from the carbon budget of a nodepool in the current budget period.",
+		},
+		[]string{
+			metrics.NodePoolLabel,
+		},
+	)
+	nodePoolCarbonBudgetRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: nodePoolSubsystem,
+			Name:      "carbon_budget_remaining_kgco2e",
+			Help:      "Kilograms of CO2 equivalent remaining in the carbon budget of a nodepool in the current budget period.",
+		},
+		[]string{
+			metrics.NodePoolLabel,
+		},
+	)
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(nodeCarbonEmissions, nodePoolCarbonBudgetConsumed, nodePoolCarbonBudgetRemaining)
+}
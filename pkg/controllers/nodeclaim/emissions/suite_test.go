@@ -0,0 +1,387 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emissions_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/awslabs/operatorpkg/status"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclaim/emissions"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/operator/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var awsEnv *test.Environment
+var env *coretest.Environment
+var fakeClock *clock.FakeClock
+var recorder *coretest.EventRecorder
+var emissionsController *emissions.Controller
+var terminationController *emissions.TerminationController
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "EmissionsController")
+}
+
+var _ = BeforeSuite(func() {
+	env = coretest.NewEnvironment(scheme.Scheme, coretest.WithCRDs(apis.CRDs...))
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options())
+	awsEnv = test.NewEnvironment(ctx, env)
+	fakeClock = clock.NewFakeClock(time.Now())
+	recorder = coretest.NewEventRecorder()
+	emissionsController = emissions.NewController(env.Client, fakeClock, recorder, awsEnv.RegionProvider)
+	terminationController = emissions.NewTerminationController(env.Client, emissionsController)
+})
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	awsEnv.Reset()
+	recorder.Reset()
+	Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypes(ctx)).To(Succeed())
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = Describe("EmissionsController", func() {
+	var nodeClass *v1beta1.EC2NodeClass
+	var nodePool *corev1beta1.NodePool
+	var nodeClaim *corev1beta1.NodeClaim
+
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass()
+		nodePool = coretest.NodePool()
+		nodeClaim = coretest.NodeClaim(corev1beta1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					corev1beta1.NodePoolLabelKey:     nodePool.Name,
+					corev1beta1.CapacityTypeLabelKey: corev1beta1.CapacityTypeOnDemand,
+					v1.LabelInstanceTypeStable:       "m5.large",
+					v1.LabelTopologyZone:             "test-zone-1a",
+				},
+			},
+			Spec: corev1beta1.NodeClaimSpec{
+				NodeClassRef: &corev1beta1.NodeClassReference{
+					Name: nodeClass.Name,
+				},
+			},
+			Status: corev1beta1.NodeClaimStatus{
+				ProviderID: fake.ProviderID(fake.InstanceID()),
+				Conditions: []status.Condition{
+					{
+						Type:               corev1beta1.ConditionTypeLaunched,
+						Status:             metav1.ConditionTrue,
+						Reason:             corev1beta1.ConditionTypeLaunched,
+						LastTransitionTime: metav1.Time{Time: fakeClock.Now()},
+					},
+				},
+			},
+		})
+	})
+	emissionsMetric := func() (float64, bool) {
+		metric, ok := FindMetricWithLabelValues("karpenter_nodes_carbon_emissions_grams_total", map[string]string{
+			"nodepool":      nodePool.Name,
+			"zone":          "test-zone-1a",
+			"capacity_type": corev1beta1.CapacityTypeOnDemand,
+		})
+		if !ok {
+			return 0, false
+		}
+		return aws.Float64Value(metric.GetCounter().Value), true
+	}
+	emissionsAnnotation := func() float64 {
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Annotations).To(HaveKey(v1beta1.AnnotationEmissions))
+		grams, err := strconv.ParseFloat(nodeClaim.Annotations[v1beta1.AnnotationEmissions], 64)
+		Expect(err).ToNot(HaveOccurred())
+		return grams
+	}
+
+	It("should requeue to account for emissions", func() {
+		result := ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+		Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+	})
+	It("should account for the emissions of a nodeclaim since it launched", func() {
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		fakeClock.Step(time.Hour)
+		ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+		gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", false)
+		Expect(ok).To(BeTrue())
+		Expect(emissionsAnnotation()).To(BeNumerically("~", gramsPerHour, 0.01))
+		Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationEmissionsAccountedAt, fakeClock.Now().UTC().Format(time.RFC3339)))
+		value, ok := emissionsMetric()
+		Expect(ok).To(BeTrue())
+		Expect(value).To(BeNumerically("~", gramsPerHour))
+
+		fakeClock.Step(30 * time.Minute)
+		ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+		Expect(emissionsAnnotation()).To(BeNumerically("~", gramsPerHour*1.5, 0.01))
+		value, ok = emissionsMetric()
+		Expect(ok).To(BeTrue())
+		Expect(value).To(BeNumerically("~", gramsPerHour*1.5))
+	})
+	It("should resume accounting from the persisted total", func() {
+		nodeClaim.Annotations = map[string]string{
+			v1beta1.AnnotationEmissions:            "100.00",
+			v1beta1.AnnotationEmissionsAccountedAt: fakeClock.Now().UTC().Format(time.RFC3339),
+		}
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		fakeClock.Step(time.Hour)
+		ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+		gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", false)
+		Expect(ok).To(BeTrue())
+		Expect(emissionsAnnotation()).To(BeNumerically("~", 100+gramsPerHour, 1))
+	})
+	It("should include embodied emissions when the nodeclass includes them", func() {
+		nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100), IncludeEmbodied: lo.ToPtr(true)}
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		fakeClock.Step(time.Hour)
+		ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+
+		gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", true)
+		Expect(ok).To(BeTrue())
+		operational, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", false)
+		Expect(ok).To(BeTrue())
+		Expect(gramsPerHour).To(BeNumerically(">", operational))
+		Expect(emissionsAnnotation()).To(BeNumerically("~", gramsPerHour, 0.01))
+	})
+	It("should not account for nodeclaims that haven't launched", func() {
+		nodeClaim.Status.ProviderID = ""
+		nodeClaim.Status.Conditions = nil
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		fakeClock.Step(time.Hour)
+		ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Annotations).ToNot(HaveKey(v1beta1.AnnotationEmissions))
+		_, ok := emissionsMetric()
+		Expect(ok).To(BeFalse())
+	})
+	Context("Termination", func() {
+		// terminate deletes the NodeClaim and removes its termination finalizer, as if its instance had been terminated
+		terminate := func() {
+			GinkgoHelper()
+			Expect(env.Client.Delete(ctx, nodeClaim)).To(Succeed())
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			controllerutil.RemoveFinalizer(nodeClaim, corev1beta1.TerminationFinalizer)
+			Expect(env.Client.Update(ctx, nodeClaim)).To(Succeed())
+		}
+		BeforeEach(func() {
+			nodeClaim.Finalizers = []string{corev1beta1.TerminationFinalizer}
+		})
+		It("should add the emissions finalizer to nodeclaims", func() {
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Finalizers).To(ContainElement(v1beta1.EmissionsFinalizer))
+		})
+		It("should wait for the instance to be terminated before settling", func() {
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			Expect(env.Client.Delete(ctx, nodeClaim)).To(Succeed())
+			fakeClock.Step(time.Hour)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Finalizers).To(ContainElement(v1beta1.EmissionsFinalizer))
+			Expect(recorder.Calls("EmissionsAccounted")).To(Equal(0))
+		})
+		It("should settle and publish the final emissions of a nodeclaim that was never accounted for", func() {
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			fakeClock.Step(time.Hour)
+			terminate()
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			ExpectNotFound(ctx, env.Client, nodeClaim)
+			Expect(recorder.Calls("EmissionsAccounted")).To(Equal(1))
+			gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", false)
+			Expect(ok).To(BeTrue())
+			value, ok := emissionsMetric()
+			Expect(ok).To(BeTrue())
+			Expect(value).To(BeNumerically("~", gramsPerHour))
+		})
+		It("should settle the emissions since the nodeclaim was last accounted for", func() {
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			fakeClock.Step(time.Hour)
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", false)
+			Expect(ok).To(BeTrue())
+			Expect(emissionsAnnotation()).To(BeNumerically("~", gramsPerHour, 0.01))
+
+			fakeClock.Step(3 * time.Minute)
+			terminate()
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(terminationController.Reconcile(ctx, nodeClaim)).To(Equal(reconcile.Result{}))
+			grams, err := strconv.ParseFloat(nodeClaim.Annotations[v1beta1.AnnotationEmissions], 64)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(grams).To(BeNumerically("~", gramsPerHour*(1+3.0/60), 0.02))
+			ExpectNotFound(ctx, env.Client, nodeClaim)
+			Expect(recorder.Calls("EmissionsAccounted")).To(Equal(1))
+		})
+		It("should not account for settled nodeclaims in the periodic pass", func() {
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			fakeClock.Step(time.Hour)
+			terminate()
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).ToNot(HaveKey(v1beta1.AnnotationEmissions))
+		})
+		It("should remove the finalizer from nodeclaims that never launched", func() {
+			nodeClaim.Status.ProviderID = ""
+			nodeClaim.Status.Conditions = nil
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			terminate()
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			ExpectNotFound(ctx, env.Client, nodeClaim)
+			Expect(recorder.Calls("EmissionsAccounted")).To(Equal(0))
+		})
+	})
+	Context("Carbon Budgets", func() {
+		BeforeEach(func() {
+			nodePool.Annotations = map[string]string{
+				v1beta1.AnnotationCarbonBudget: "1000",
+			}
+		})
+		budgetAnnotation := func(key string) float64 {
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			Expect(nodePool.Annotations).To(HaveKey(key))
+			kg, err := strconv.ParseFloat(nodePool.Annotations[key], 64)
+			Expect(err).ToNot(HaveOccurred())
+			return kg
+		}
+		quarterStart := func(t time.Time) time.Time {
+			t = t.UTC()
+			return time.Date(t.Year(), t.Month()-(t.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC)
+		}
+
+		It("should consume the emissions of the nodepool's nodeclaims from its budget", func() {
+			ExpectApplied(ctx, env.Client, nodeClass, nodePool, nodeClaim)
+			fakeClock.Step(time.Hour)
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", false)
+			Expect(ok).To(BeTrue())
+
+			Expect(budgetAnnotation(v1beta1.AnnotationCarbonBudgetConsumed)).To(BeNumerically("~", gramsPerHour/1000, 0.001))
+			Expect(budgetAnnotation(v1beta1.AnnotationCarbonBudgetRemaining)).To(BeNumerically("~", 1000-gramsPerHour/1000, 0.001))
+			Expect(nodePool.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationCarbonBudgetPeriodStart, quarterStart(fakeClock.Now()).Format(time.RFC3339)))
+			metric, ok := FindMetricWithLabelValues("karpenter_nodepool_carbon_budget_remaining_kgco2e", map[string]string{"nodepool": nodePool.Name})
+			Expect(ok).To(BeTrue())
+			Expect(metric.GetGauge().GetValue()).To(BeNumerically("~", 1000-gramsPerHour/1000, 0.001))
+
+			fakeClock.Step(time.Hour)
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			Expect(budgetAnnotation(v1beta1.AnnotationCarbonBudgetConsumed)).To(BeNumerically("~", 2*gramsPerHour/1000, 0.001))
+		})
+		It("should start consuming afresh when a new budget period begins", func() {
+			nodePool.Annotations[v1beta1.AnnotationCarbonBudgetPeriodStart] = quarterStart(fakeClock.Now()).AddDate(0, -3, 0).Format(time.RFC3339)
+			nodePool.Annotations[v1beta1.AnnotationCarbonBudgetConsumed] = "900.000"
+			ExpectApplied(ctx, env.Client, nodeClass, nodePool, nodeClaim)
+			fakeClock.Step(time.Hour)
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", false)
+			Expect(ok).To(BeTrue())
+			Expect(budgetAnnotation(v1beta1.AnnotationCarbonBudgetConsumed)).To(BeNumerically("~", gramsPerHour/1000, 0.001))
+		})
+		It("should publish an event once the budget is exhausted", func() {
+			nodePool.Annotations[v1beta1.AnnotationCarbonBudget] = "0.001"
+			ExpectApplied(ctx, env.Client, nodeClass, nodePool, nodeClaim)
+			fakeClock.Step(time.Hour)
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			Expect(budgetAnnotation(v1beta1.AnnotationCarbonBudgetRemaining)).To(BeZero())
+			Expect(recorder.Calls("CarbonBudgetExhausted")).To(Equal(1))
+
+			fakeClock.Step(time.Hour)
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			Expect(recorder.Calls("CarbonBudgetExhausted")).To(Equal(1))
+		})
+		It("should ignore nodepools with an invalid budget", func() {
+			nodePool.Annotations[v1beta1.AnnotationCarbonBudget] = "lots"
+			ExpectApplied(ctx, env.Client, nodeClass, nodePool, nodeClaim)
+			fakeClock.Step(time.Hour)
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			Expect(nodePool.Annotations).ToNot(HaveKey(v1beta1.AnnotationCarbonBudgetConsumed))
+		})
+		It("should consume emissions that are yet to be consumed from the budget", func() {
+			nodeClaim.Annotations = map[string]string{v1beta1.AnnotationEmissionsUnbudgeted: "2000.00"}
+			ExpectApplied(ctx, env.Client, nodeClass, nodePool, nodeClaim)
+			fakeClock.Step(time.Hour)
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", false)
+			Expect(ok).To(BeTrue())
+			Expect(budgetAnnotation(v1beta1.AnnotationCarbonBudgetConsumed)).To(BeNumerically("~", 2+gramsPerHour/1000, 0.001))
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).ToNot(HaveKey(v1beta1.AnnotationEmissionsUnbudgeted))
+			Expect(emissionsAnnotation()).To(BeNumerically("~", gramsPerHour, 0.01))
+		})
+		It("should consume the emissions of settled nodeclaims from the budget", func() {
+			nodeClaim.Finalizers = []string{corev1beta1.TerminationFinalizer}
+			nodeClaim.Annotations = map[string]string{v1beta1.AnnotationEmissionsUnbudgeted: "2000.00"}
+			ExpectApplied(ctx, env.Client, nodeClass, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			fakeClock.Step(time.Hour)
+			Expect(env.Client.Delete(ctx, nodeClaim)).To(Succeed())
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			controllerutil.RemoveFinalizer(nodeClaim, corev1beta1.TerminationFinalizer)
+			Expect(env.Client.Update(ctx, nodeClaim)).To(Succeed())
+			ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClaim)
+			ExpectNotFound(ctx, env.Client, nodeClaim)
+			gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions("m5.large", "test-zone-1a", false)
+			Expect(ok).To(BeTrue())
+			Expect(budgetAnnotation(v1beta1.AnnotationCarbonBudgetConsumed)).To(BeNumerically("~", 2+gramsPerHour/1000, 0.001))
+		})
+		It("should clear emissions that are yet to be consumed once the nodepool has no budget", func() {
+			nodePool.Annotations = nil
+			nodeClaim.Annotations = map[string]string{v1beta1.AnnotationEmissionsUnbudgeted: "2000.00"}
+			ExpectApplied(ctx, env.Client, nodeClass, nodePool, nodeClaim)
+			fakeClock.Step(time.Hour)
+			ExpectReconcileSucceeded(ctx, emissionsController, types.NamespacedName{})
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).ToNot(HaveKey(v1beta1.AnnotationEmissionsUnbudgeted))
+		})
+	})
+})
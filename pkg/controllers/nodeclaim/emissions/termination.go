@@ -0,0 +1,136 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emissions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awslabs/operatorpkg/reasonable"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+)
+
+// TerminationController settles the emissions of NodeClaims as they're deleted. Every NodeClaim is held by a finalizer
+// until its instance has been terminated, at which point the emissions since it was last accounted for are added to its
+// total, consumed from its NodePool's carbon budget, and the final total is published as an event. Since the finalizer
+// and the running total are persisted on the NodeClaim, NodeClaims that are deleted between accounting passes or across
+// controller restarts are settled all the same. The settled total is persisted in the same update that removes the
+// finalizer, so a NodeClaim is only released once its emissions have been consumed from its budget.
+type TerminationController struct {
+	kubeClient client.Client
+	accounting *Controller
+}
+
+func NewTerminationController(kubeClient client.Client, accounting *Controller) *TerminationController {
+	return &TerminationController{
+		kubeClient: kubeClient,
+		accounting: accounting,
+	}
+}
+
+func (c *TerminationController) Reconcile(ctx context.Context, nodeClaim *corev1beta1.NodeClaim) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "nodeclaim.emissions.termination")
+
+	stored := nodeClaim.DeepCopy()
+	settled := 0.0
+	if nodeClaim.DeletionTimestamp.IsZero() {
+		controllerutil.AddFinalizer(nodeClaim, v1beta1.EmissionsFinalizer)
+	} else {
+		if !controllerutil.ContainsFinalizer(nodeClaim, v1beta1.EmissionsFinalizer) {
+			return reconcile.Result{}, nil
+		}
+		// The instance keeps running until the NodeClaim's termination finalizer is removed
+		if !settling(nodeClaim) {
+			return reconcile.Result{}, nil
+		}
+		grams, err := c.settle(ctx, nodeClaim)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		settled = grams
+		controllerutil.RemoveFinalizer(nodeClaim, v1beta1.EmissionsFinalizer)
+	}
+	if !equality.Semantic.DeepEqual(stored.Finalizers, nodeClaim.Finalizers) {
+		// We call Update() here rather than Patch() because patching a list with a JSON merge patch
+		// can cause races due to the fact that it fully replaces the list on a change
+		// https://github.com/kubernetes/kubernetes/issues/111643#issuecomment-2016489732
+		if err := c.kubeClient.Update(ctx, nodeClaim); err != nil {
+			if errors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, client.IgnoreNotFound(fmt.Errorf("updating emissions finalizer, %w", err))
+		}
+	}
+	if !nodeClaim.DeletionTimestamp.IsZero() && nodeClaim.Status.ProviderID != "" {
+		observe(ctx, nodeClaim, settled)
+		c.accounting.recorder.Publish(NodeClaimEmissionsEvent(nodeClaim, emissions(nodeClaim)))
+	}
+	return reconcile.Result{}, nil
+}
+
+// settle consumes the emissions of a NodeClaim that are yet to be consumed from its NodePool's carbon budget, including
+// those since it was last accounted for, and adds the latter to its running total. It returns the emissions since the
+// NodeClaim was last accounted for. NodeClaims that never launched have no emissions to settle.
+func (c *TerminationController) settle(ctx context.Context, nodeClaim *corev1beta1.NodeClaim) (float64, error) {
+	if nodeClaim.Status.ProviderID == "" {
+		return 0, nil
+	}
+	nodeClass, err := c.accounting.resolveNodeClass(ctx, nodeClaim, map[string]*v1beta1.EC2NodeClass{})
+	if err != nil {
+		return 0, err
+	}
+	now := c.accounting.clock.Now()
+	grams, ok := c.accounting.accrue(ctx, nodeClaim, nodeClass, now)
+	if !ok {
+		grams = 0
+	}
+	if nodePoolName, ok := nodeClaim.Labels[corev1beta1.NodePoolLabelKey]; ok && grams+unbudgeted(nodeClaim) > 0 {
+		if err := c.accounting.consumeNodePoolBudget(ctx, nodePoolName, grams+unbudgeted(nodeClaim), now); err != nil {
+			return 0, fmt.Errorf("consuming carbon budget, %w", err)
+		}
+	}
+	// If the NodeClaim fails to be updated after its budget has been consumed, the emissions are consumed again when it's
+	// next settled, so that they're never missing from the budget
+	accountFor(nodeClaim, grams, 0, now)
+	return grams, nil
+}
+
+// settling returns true if a NodeClaim's instance has been terminated, so that its emissions can be settled
+func settling(nodeClaim *corev1beta1.NodeClaim) bool {
+	return !nodeClaim.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(nodeClaim, corev1beta1.TerminationFinalizer)
+}
+
+func (c *TerminationController) Register(_ context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("nodeclaim.emissions.termination").
+		For(&corev1beta1.NodeClaim{}).
+		WithOptions(controller.Options{
+			RateLimiter:             reasonable.RateLimiter(),
+			MaxConcurrentReconciles: 10,
+		}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	corecloudprovider "sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/events"
@@ -65,7 +66,7 @@ var _ = BeforeSuite(func() {
 	env = coretest.NewEnvironment(scheme.Scheme, coretest.WithCRDs(apis.CRDs...))
 	awsEnv = test.NewEnvironment(ctx, env)
 	cloudProvider = cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, events.NewRecorder(&record.FakeRecorder{}),
-		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider)
+		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.CarbonProvider, awsEnv.RegionProvider, clock.RealClock{})
 	garbageCollectionController = garbagecollection.NewController(env.Client, cloudProvider)
 })
 
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,21 +36,30 @@ import (
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
 	"github.com/aws/karpenter-provider-aws/pkg/utils"
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 )
 
+// carbonTags maps the annotations that CloudProvider.Create records the carbon details of a launch in to the tags that
+// they're written to, so that emissions can be joined to billing data such as Cost and Usage Reports
+var carbonTags = map[string]string{
+	v1beta1.AnnotationCarbonIntensityAtLaunch: v1beta1.TagCarbonIntensityAtLaunch,
+	v1beta1.AnnotationEstimatedWatts:          v1beta1.TagEstimatedWatts,
+	v1beta1.AnnotationCarbonMode:              v1beta1.TagCarbonMode,
+}
+
 type Controller struct {
-	kubeClient       client.Client
-	instanceProvider instance.Provider
+	kubeClient     client.Client
+	regionProvider region.Provider
 }
 
-func NewController(kubeClient client.Client, instanceProvider instance.Provider) *Controller {
+func NewController(kubeClient client.Client, regionProvider region.Provider) *Controller {
 	return &Controller{
-		kubeClient:       kubeClient,
-		instanceProvider: instanceProvider,
+		kubeClient:     kubeClient,
+		regionProvider: regionProvider,
 	}
 }
 
@@ -98,24 +108,48 @@ func (c *Controller) tagInstance(ctx context.Context, nc *corev1beta1.NodeClaim,
 		v1beta1.TagName:      nc.Status.NodeName,
 		v1beta1.TagNodeClaim: nc.Name,
 	}
+	// Carbon details are recorded at launch, so NodeClaims launched before they were recorded aren't tagged with them
+	carbon := map[string]string{}
+	for annotation, tag := range carbonTags {
+		if value, ok := nc.Annotations[annotation]; ok {
+			carbon[tag] = value
+		}
+	}
+
+	// The instance is tagged through the EC2 API of the region that it was launched in
+	providers, err := c.regionProvider.ForZone(ctx, nc.Labels[v1.LabelTopologyZone])
+	if err != nil {
+		return fmt.Errorf("tagging nodeclaim, %w", err)
+	}
 
 	// Remove tags which have been already populated
-	instance, err := c.instanceProvider.Get(ctx, id)
+	instance, err := providers.InstanceProvider.Get(ctx, id)
 	if err != nil {
 		return fmt.Errorf("tagging nodeclaim, %w", err)
 	}
 	tags = lo.OmitByKeys(tags, lo.Keys(instance.Tags))
-	if len(tags) == 0 {
-		return nil
+	carbon = lo.OmitByKeys(carbon, lo.Keys(instance.Tags))
+	if len(tags) > 0 {
+		if err := createTags(ctx, providers.InstanceProvider, id, tags); err != nil {
+			return fmt.Errorf("tagging nodeclaim, %w", err)
+		}
+	}
+	// Carbon details are tagged separately, so that a controller role that isn't allowed to create them can't prevent
+	// the instance from being tagged with its Name and NodeClaim. The NodeClaim isn't marked as tagged until they are, so
+	// that they're retried.
+	if len(carbon) > 0 {
+		if err := createTags(ctx, providers.InstanceProvider, id, carbon); err != nil {
+			return fmt.Errorf("tagging nodeclaim with carbon details, %w", err)
+		}
 	}
+	return nil
+}
 
+func createTags(ctx context.Context, instanceProvider instance.Provider, id string, tags map[string]string) error {
 	// Ensures that no more than 1 CreateTags call is made per second. Rate limiting is required since CreateTags
 	// shares a pool with other mutating calls (e.g. CreateFleet).
 	defer time.Sleep(time.Second)
-	if err := c.instanceProvider.CreateTags(ctx, id, tags); err != nil {
-		return fmt.Errorf("tagging nodeclaim, %w", err)
-	}
-	return nil
+	return instanceProvider.CreateTags(ctx, id, tags)
 }
 
 func isTaggable(nc *corev1beta1.NodeClaim) bool {
@@ -59,7 +59,7 @@ var _ = BeforeSuite(func() {
 	ctx = coreoptions.ToContext(ctx, coretest.Options())
 	ctx = options.ToContext(ctx, test.Options())
 	awsEnv = test.NewEnvironment(ctx, env)
-	taggingController = tagging.NewController(env.Client, awsEnv.InstanceProvider)
+	taggingController = tagging.NewController(env.Client, awsEnv.RegionProvider)
 })
 var _ = AfterSuite(func() {
 	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
@@ -184,6 +184,108 @@ var _ = Describe("TaggingController", func() {
 		})).To(BeFalse())
 	})
 
+	It("should tag instances with the carbon details of their launch", func() {
+		nodeClaim := coretest.NodeClaim(corev1beta1.NodeClaim{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{
+					v1beta1.AnnotationCarbonIntensityAtLaunch: "250",
+					v1beta1.AnnotationEstimatedWatts:          "8",
+					v1beta1.AnnotationCarbonMode:              string(v1beta1.CarbonModeWeighted),
+				},
+			},
+			Status: corev1beta1.NodeClaimStatus{
+				ProviderID: fake.ProviderID(*ec2Instance.InstanceId),
+				NodeName:   "default",
+			},
+		})
+
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+		instanceTags := instance.NewInstance(ec2Instance).Tags
+		Expect(instanceTags).To(HaveKeyWithValue(v1beta1.TagCarbonIntensityAtLaunch, "250"))
+		Expect(instanceTags).To(HaveKeyWithValue(v1beta1.TagEstimatedWatts, "8"))
+		Expect(instanceTags).To(HaveKeyWithValue(v1beta1.TagCarbonMode, string(v1beta1.CarbonModeWeighted)))
+	})
+
+	It("shouldn't tag instances with carbon details that weren't recorded at launch", func() {
+		nodeClaim := coretest.NodeClaim(corev1beta1.NodeClaim{
+			Status: corev1beta1.NodeClaimStatus{
+				ProviderID: fake.ProviderID(*ec2Instance.InstanceId),
+				NodeName:   "default",
+			},
+		})
+
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+		instanceTags := instance.NewInstance(ec2Instance).Tags
+		Expect(instanceTags).To(HaveKey(v1beta1.TagName))
+		Expect(instanceTags).To(HaveKey(v1beta1.TagNodeClaim))
+		Expect(awsEnv.EC2API.CreateTagsBehavior.Calls()).To(Equal(1))
+		awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.ForEach(func(input *ec2.CreateTagsInput) {
+			for _, tag := range input.Tags {
+				Expect(*tag.Key).ToNot(BeElementOf(v1beta1.TagCarbonIntensityAtLaunch, v1beta1.TagEstimatedWatts, v1beta1.TagCarbonMode))
+			}
+		})
+	})
+
+	It("should tag instances with their carbon details separately from their name and nodeclaim", func() {
+		nodeClaim := coretest.NodeClaim(corev1beta1.NodeClaim{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{
+					v1beta1.AnnotationCarbonMode: string(v1beta1.CarbonModeStrict),
+				},
+			},
+			Status: corev1beta1.NodeClaimStatus{
+				ProviderID: fake.ProviderID(*ec2Instance.InstanceId),
+				NodeName:   "default",
+			},
+		})
+
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+		Expect(awsEnv.EC2API.CreateTagsBehavior.Calls()).To(Equal(2))
+		var keys [][]string
+		awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.ForEach(func(input *ec2.CreateTagsInput) {
+			keys = append(keys, lo.Map(input.Tags, func(tag *ec2.Tag, _ int) string { return *tag.Key }))
+		})
+		Expect(keys).To(ConsistOf(
+			ConsistOf(v1beta1.TagName, v1beta1.TagNodeClaim),
+			ConsistOf(v1beta1.TagCarbonMode),
+		))
+	})
+
+	It("should retry tagging instances whose carbon details can't be tagged", func() {
+		nodeClaim := coretest.NodeClaim(corev1beta1.NodeClaim{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{
+					v1beta1.AnnotationCarbonMode: string(v1beta1.CarbonModeStrict),
+				},
+			},
+			Status: corev1beta1.NodeClaimStatus{
+				ProviderID: fake.ProviderID(*ec2Instance.InstanceId),
+				NodeName:   "default",
+			},
+		})
+		// Only the carbon details are left to tag, so the failure is injected into their CreateTags call
+		ec2Instance.Tags = append(ec2Instance.Tags,
+			&ec2.Tag{Key: aws.String(v1beta1.TagName), Value: aws.String(nodeClaim.Status.NodeName)},
+			&ec2.Tag{Key: aws.String(v1beta1.TagNodeClaim), Value: aws.String(nodeClaim.Name)},
+		)
+		awsEnv.EC2API.Instances.Store(*ec2Instance.InstanceId, ec2Instance)
+		awsEnv.EC2API.CreateTagsBehavior.Error.Set(fmt.Errorf("UnauthorizedOperation"))
+
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		_ = ExpectObjectReconcileFailed(ctx, env.Client, taggingController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Annotations).ToNot(HaveKey(v1beta1.AnnotationInstanceTagged))
+		Expect(instance.NewInstance(ec2Instance).Tags).ToNot(HaveKey(v1beta1.TagCarbonMode))
+
+		ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Annotations).To(HaveKey(v1beta1.AnnotationInstanceTagged))
+		Expect(instance.NewInstance(ec2Instance).Tags).To(HaveKeyWithValue(v1beta1.TagCarbonMode, string(v1beta1.CarbonModeStrict)))
+	})
+
 	DescribeTable(
 		"should tag taggable instances",
 		func(customTags ...string) {
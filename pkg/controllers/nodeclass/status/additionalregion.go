@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/multierr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
+)
+
+type AdditionalRegion struct {
+	regionProvider region.Provider
+}
+
+// Reconcile resolves the subnets, security groups and AMIs of each of the EC2NodeClass's additional regions with the
+// providers for that region. A region that fails to resolve is kept out of the status, so that the EC2NodeClass still
+// launches into its other regions.
+func (a *AdditionalRegion) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (reconcile.Result, error) {
+	var statuses []v1beta1.AdditionalRegionStatus
+	var errs error
+	for _, additionalRegion := range nodeClass.Spec.AdditionalRegions {
+		if additionalRegion.Name == a.regionProvider.Region() {
+			log.FromContext(ctx).WithValues("region", additionalRegion.Name).V(1).Info("ignoring additional region, it's the cluster's region")
+			continue
+		}
+		status, err := a.resolve(ctx, nodeClass, additionalRegion)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("resolving region %s, %w", additionalRegion.Name, err))
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	nodeClass.Status.AdditionalRegions = statuses
+	if errs != nil {
+		return reconcile.Result{}, errs
+	}
+	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+func (a *AdditionalRegion) resolve(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, additionalRegion v1beta1.AdditionalRegion) (v1beta1.AdditionalRegionStatus, error) {
+	providers, err := a.regionProvider.Get(ctx, additionalRegion.Name)
+	if err != nil {
+		return v1beta1.AdditionalRegionStatus{}, err
+	}
+	// The regional EC2NodeClass is resolved by the same reconcilers as the EC2NodeClass, with the providers of the region
+	regional := region.SelectorNodeClass(nodeClass, additionalRegion)
+	for _, reconciler := range []nodeClassStatusReconciler{
+		&AMI{amiProvider: providers.AMIProvider},
+		&Subnet{subnetProvider: providers.SubnetProvider},
+		&SecurityGroup{securityGroupProvider: providers.SecurityGroupProvider},
+	} {
+		if _, err := reconciler.Reconcile(ctx, regional); err != nil {
+			return v1beta1.AdditionalRegionStatus{}, err
+		}
+	}
+	return v1beta1.AdditionalRegionStatus{
+		Name:           additionalRegion.Name,
+		Subnets:        regional.Status.Subnets,
+		SecurityGroups: regional.Status.SecurityGroups,
+		AMIs:           regional.Status.AMIs,
+	}, nil
+}
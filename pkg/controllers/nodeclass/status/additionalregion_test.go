@@ -0,0 +1,135 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+)
+
+var _ = Describe("NodeClass Additional Region Status Controller", func() {
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
+			Spec: v1beta1.EC2NodeClassSpec{
+				SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				AMISelectorTerms: []v1beta1.AMISelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				AdditionalRegions: []v1beta1.AdditionalRegion{
+					{
+						Name: "us-east-1",
+						SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{
+							{
+								Tags: map[string]string{"*": "*"},
+							},
+						},
+						SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{
+							{
+								Tags: map[string]string{"*": "*"},
+							},
+						},
+					},
+				},
+			},
+		})
+		awsEnv.RegionalEC2API.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{
+			{
+				SubnetId:                aws.String("subnet-east1"),
+				AvailabilityZone:        aws.String("us-east-1a"),
+				AvailabilityZoneId:      aws.String("use1-az1"),
+				AvailableIpAddressCount: aws.Int64(100),
+				Tags:                    []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-subnet-east1")}},
+			},
+		}})
+		awsEnv.RegionalEC2API.DescribeSecurityGroupsOutput.Set(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{
+			{
+				GroupId:   aws.String("sg-east1"),
+				GroupName: aws.String("securityGroup-east1"),
+				Tags:      []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-security-group-east1")}},
+			},
+		}})
+	})
+	It("Should update EC2NodeClass status for additional regions with the region's subnets and security groups", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AdditionalRegions).To(HaveLen(1))
+		Expect(nodeClass.Status.AdditionalRegions[0].Name).To(Equal("us-east-1"))
+		Expect(nodeClass.Status.AdditionalRegions[0].Subnets).To(Equal([]v1beta1.Subnet{
+			{
+				ID:     "subnet-east1",
+				Zone:   "us-east-1a",
+				ZoneID: "use1-az1",
+			},
+		}))
+		Expect(nodeClass.Status.AdditionalRegions[0].SecurityGroups).To(Equal([]v1beta1.SecurityGroup{
+			{
+				ID:   "sg-east1",
+				Name: "securityGroup-east1",
+			},
+		}))
+		Expect(nodeClass.Status.AdditionalRegions[0].AMIs).ToNot(BeEmpty())
+		// The cluster's region is resolved as before
+		Expect(nodeClass.Status.Subnets).ToNot(ContainElement(HaveField("ID", "subnet-east1")))
+	})
+	It("Should leave a region that fails to resolve out of the EC2NodeClass status", func() {
+		awsEnv.RegionalEC2API.NextError.Set(fmt.Errorf("failed"))
+		ExpectApplied(ctx, env.Client, nodeClass)
+		_ = ExpectObjectReconcileFailed(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AdditionalRegions).To(BeEmpty())
+		Expect(nodeClass.Status.Subnets).ToNot(BeEmpty())
+	})
+	It("Should ignore an additional region that is the cluster's region", func() {
+		nodeClass.Spec.AdditionalRegions[0].Name = fake.DefaultRegion
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AdditionalRegions).To(BeEmpty())
+	})
+	It("Should clear the status of additional regions once they're removed", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AdditionalRegions).To(HaveLen(1))
+
+		nodeClass.Spec.AdditionalRegions = nil
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AdditionalRegions).To(BeEmpty())
+	})
+})
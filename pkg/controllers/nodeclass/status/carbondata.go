@@ -0,0 +1,90 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/utils"
+)
+
+type CarbonData struct {
+	carbonProvider carbon.Provider
+}
+
+// Reconcile reports where the carbon intensity of the EC2NodeClass's zones comes from. Without a carbon intensity
+// endpoint, the embedded dataset is the intended source. With one, zones that have fallen back to the embedded dataset
+// because their live data is missing or older than the TTL are stale.
+func (c *CarbonData) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (reconcile.Result, error) {
+	if nodeClass.Spec.CarbonPolicy == nil || nodeClass.Spec.CarbonPolicy.Mode == v1beta1.CarbonModeOff {
+		if err := nodeClass.StatusConditions().Clear(v1beta1.ConditionTypeCarbonDataReady); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+	zones := zones(nodeClass)
+	if len(zones) == 0 {
+		return reconcile.Result{}, nil
+	}
+	opts := options.FromContext(ctx)
+	if opts.CarbonIntensityURL == "" {
+		nodeClass.StatusConditions().SetTrueWithReason(v1beta1.ConditionTypeCarbonDataReady, string(carbon.DataSourceStatic),
+			"Using the embedded per-region carbon intensity dataset, no carbon intensity endpoint is configured")
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+	var stale []string
+	var lastUpdated time.Time
+	for _, zone := range zones {
+		source, updated := c.carbonProvider.ZoneDataSource(zone)
+		if source != carbon.DataSourceLive {
+			stale = append(stale, zone)
+			continue
+		}
+		// The oldest update is reported, since that's how stale the data of the EC2NodeClass can be
+		if lastUpdated.IsZero() || updated.Before(lastUpdated) {
+			lastUpdated = updated
+		}
+	}
+	if len(stale) != 0 {
+		nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypeCarbonDataReady, "StaleData",
+			fmt.Sprintf("Using the embedded per-region carbon intensity dataset for zones %s, live data is unavailable or older than %s",
+				utils.PrettySlice(stale, 5), opts.CarbonIntensityTTL))
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+	nodeClass.StatusConditions().SetTrueWithReason(v1beta1.ConditionTypeCarbonDataReady, string(carbon.DataSourceLive),
+		fmt.Sprintf("Using live carbon intensity data, last updated at %s and stale after %s",
+			lastUpdated.UTC().Format(time.RFC3339), opts.CarbonIntensityTTL))
+	return reconcile.Result{RequeueAfter: time.Minute}, nil
+}
+
+// zones returns the zones of the subnets of an EC2NodeClass, in the cluster's region and in its additional regions
+func zones(nodeClass *v1beta1.EC2NodeClass) []string {
+	subnets := append([]v1beta1.Subnet{}, nodeClass.Status.Subnets...)
+	for _, additionalRegion := range nodeClass.Status.AdditionalRegions {
+		subnets = append(subnets, additionalRegion.Subnets...)
+	}
+	zones := lo.Uniq(lo.Map(subnets, func(s v1beta1.Subnet, _ int) string { return s.Zone }))
+	sort.Strings(zones)
+	return zones
+}
@@ -0,0 +1,116 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"github.com/awslabs/operatorpkg/status"
+	"github.com/samber/lo"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+)
+
+var _ = Describe("NodeClass Carbon Data Status Controller", func() {
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
+			Spec: v1beta1.EC2NodeClassSpec{
+				SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				AMISelectorTerms: []v1beta1.AMISelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				CarbonPolicy: &v1beta1.CarbonPolicy{
+					Mode:         v1beta1.CarbonModeStrict,
+					MaxIntensity: lo.ToPtr[int64](400),
+				},
+			},
+		})
+		awsEnv.CarbonIntensityAPI.SetIntensity(carbon.IntensityResponse{Zone: "US-NW-PACW", CarbonIntensity: 300})
+	})
+	It("should not set the CarbonDataReady condition when there is no carbon policy", func() {
+		nodeClass.Spec.CarbonPolicy = nil
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeCarbonDataReady)).To(BeNil())
+	})
+	It("should report the embedded dataset when there is no carbon intensity endpoint", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		condition := nodeClass.StatusConditions().Get(v1beta1.ConditionTypeCarbonDataReady)
+		Expect(condition.IsTrue()).To(BeTrue())
+		Expect(condition.Reason).To(Equal(string(carbon.DataSourceStatic)))
+		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsTrue()).To(BeTrue())
+	})
+	It("should report live data and when it was last updated", func() {
+		ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+			CarbonIntensityURL:         lo.ToPtr(awsEnv.CarbonIntensityAPI.URL),
+			CarbonIntensityZoneMapping: lo.ToPtr("us-west-2=US-NW-PACW"),
+		}))
+		Expect(awsEnv.CarbonProvider.Update(ctx)).To(Succeed())
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		condition := nodeClass.StatusConditions().Get(v1beta1.ConditionTypeCarbonDataReady)
+		Expect(condition.IsTrue()).To(BeTrue())
+		Expect(condition.Reason).To(Equal(string(carbon.DataSourceLive)))
+		Expect(condition.Message).To(ContainSubstring("last updated at"))
+		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsTrue()).To(BeTrue())
+	})
+	It("should report stale data and not be ready in Strict mode when zones have fallen back to the embedded dataset", func() {
+		ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+			CarbonIntensityURL:         lo.ToPtr(awsEnv.CarbonIntensityAPI.URL),
+			CarbonIntensityZoneMapping: lo.ToPtr("us-west-2=US-NW-PACW"),
+		}))
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		condition := nodeClass.StatusConditions().Get(v1beta1.ConditionTypeCarbonDataReady)
+		Expect(condition.IsFalse()).To(BeTrue())
+		Expect(condition.Reason).To(Equal("StaleData"))
+		Expect(condition.Message).To(ContainSubstring("test-zone-1a"))
+		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).Message).To(Equal("Carbon intensity data is stale"))
+	})
+	It("should stay ready with stale data when the carbon policy isn't Strict", func() {
+		ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+			CarbonIntensityURL:         lo.ToPtr(awsEnv.CarbonIntensityAPI.URL),
+			CarbonIntensityZoneMapping: lo.ToPtr("us-west-2=US-NW-PACW"),
+		}))
+		nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeCarbonDataReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsTrue()).To(BeTrue())
+	})
+})
@@ -58,6 +58,13 @@ func (n Readiness) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClas
 			return reconcile.Result{}, fmt.Errorf("failed to detect the cluster CIDR, %w", err)
 		}
 	}
+	// Strict mode refuses offerings based on the intensity of their zones, so it doesn't launch on stale intensity
+	if nodeClass.Spec.CarbonPolicy != nil && nodeClass.Spec.CarbonPolicy.Mode == v1beta1.CarbonModeStrict {
+		if condition := nodeClass.StatusConditions().Get(v1beta1.ConditionTypeCarbonDataReady); condition != nil && condition.IsFalse() {
+			nodeClass.StatusConditions().SetFalse(status.ConditionReady, "NodeClassNotReady", "Carbon intensity data is stale")
+			return reconcile.Result{}, nil
+		}
+	}
 	nodeClass.StatusConditions().SetTrue(status.ConditionReady)
 	return reconcile.Result{}, nil
 }
@@ -59,6 +59,8 @@ var _ = BeforeSuite(func() {
 		awsEnv.AMIProvider,
 		awsEnv.InstanceProfileProvider,
 		awsEnv.LaunchTemplateProvider,
+		awsEnv.RegionProvider,
+		awsEnv.CarbonProvider,
 	)
 })
 
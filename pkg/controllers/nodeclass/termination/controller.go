@@ -22,7 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/karpenter/pkg/operator/injection"
 
-	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
 
 	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -50,17 +50,17 @@ type Controller struct {
 	kubeClient              client.Client
 	recorder                events.Recorder
 	instanceProfileProvider instanceprofile.Provider
-	launchTemplateProvider  launchtemplate.Provider
+	regionProvider          region.Provider
 }
 
 func NewController(kubeClient client.Client, recorder events.Recorder,
-	instanceProfileProvider instanceprofile.Provider, launchTemplateProvider launchtemplate.Provider) *Controller {
+	instanceProfileProvider instanceprofile.Provider, regionProvider region.Provider) *Controller {
 
 	return &Controller{
 		kubeClient:              kubeClient,
 		recorder:                recorder,
 		instanceProfileProvider: instanceProfileProvider,
-		launchTemplateProvider:  launchTemplateProvider,
+		regionProvider:          regionProvider,
 	}
 }
 
@@ -91,8 +91,16 @@ func (c *Controller) finalize(ctx context.Context, nodeClass *v1beta1.EC2NodeCla
 			return reconcile.Result{}, fmt.Errorf("deleting instance profile, %w", err)
 		}
 	}
-	if err := c.launchTemplateProvider.DeleteAll(ctx, nodeClass); err != nil {
-		return reconcile.Result{}, fmt.Errorf("deleting launch templates, %w", err)
+	// Launch templates are created in each region that the EC2NodeClass launches into
+	regions := lo.Uniq(append([]string{c.regionProvider.Region()}, lo.Map(nodeClass.Spec.AdditionalRegions, func(r v1beta1.AdditionalRegion, _ int) string { return r.Name })...))
+	for _, name := range regions {
+		providers, err := c.regionProvider.Get(ctx, name)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("getting providers for region %s, %w", name, err)
+		}
+		if err := providers.LaunchTemplateProvider.DeleteAll(ctx, nodeClass); err != nil {
+			return reconcile.Result{}, fmt.Errorf("deleting launch templates in region %s, %w", name, err)
+		}
 	}
 	controllerutil.RemoveFinalizer(nodeClass, v1beta1.TerminationFinalizer)
 	if !equality.Semantic.DeepEqual(stored, nodeClass) {
@@ -0,0 +1,59 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/karpenter/pkg/operator/controller"
+
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+)
+
+type Controller struct {
+	carbonProvider carbon.Provider
+}
+
+func NewController(carbonProvider carbon.Provider) *Controller {
+	return &Controller{
+		carbonProvider: carbonProvider,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	if err := c.carbonProvider.Update(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("updating carbon intensity, %w", err)
+	}
+	for _, intensity := range c.carbonProvider.Intensities() {
+		gridCarbonIntensity.With(prometheus.Labels{
+			regionLabel: intensity.Region,
+			zoneLabel:   intensity.Zone,
+		}).Set(intensity.GramsPerKWh)
+	}
+	// grid intensity changes throughout the day, so we refresh far more frequently than pricing
+	return reconcile.Result{RequeueAfter: time.Hour}, nil
+}
+
+func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+	return controller.NewSingletonManagedBy(m).
+		Named("providers.carbon").
+		Complete(c)
+}
@@ -0,0 +1,47 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/karpenter/pkg/metrics"
+)
+
+const (
+	cloudProviderSubsystem = "cloudprovider"
+	regionLabel            = "region"
+	zoneLabel              = "zone"
+)
+
+var (
+	gridCarbonIntensity = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "grid_carbon_intensity",
+			Help:      "Carbon intensity, in grams of CO2 equivalent per kWh, of the electricity grid used when estimating emissions, based on region and zone. The zone is empty for region wide intensity.",
+		},
+		[]string{
+			regionLabel,
+			zoneLabel,
+		},
+	)
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(gridCarbonIntensity)
+}
@@ -0,0 +1,334 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/imdario/mergo"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/types"
+	clock "k8s.io/utils/clock/testing"
+	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/operator/scheme"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	controllerscarbon "github.com/aws/karpenter-provider-aws/pkg/controllers/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var stop context.CancelFunc
+var env *coretest.Environment
+var awsEnv *test.Environment
+var controller *controllerscarbon.Controller
+var fakeClock *clock.FakeClock
+
+func TestAWS(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Carbon")
+}
+
+var _ = BeforeSuite(func() {
+	env = coretest.NewEnvironment(scheme.Scheme, coretest.WithCRDs(apis.CRDs...))
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options())
+	ctx, stop = context.WithCancel(ctx)
+	awsEnv = test.NewEnvironment(ctx, env)
+	controller = controllerscarbon.NewController(awsEnv.CarbonProvider)
+	fakeClock = clock.NewFakeClock(time.Now())
+})
+
+var _ = AfterSuite(func() {
+	stop()
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options())
+
+	awsEnv.Reset()
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = Describe("Carbon", func() {
+	It("should requeue to refresh intensity data", func() {
+		result := ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		Expect(result.RequeueAfter).To(Equal(time.Hour))
+	})
+	It("should expose grid intensity metrics for all regions", func() {
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		for region, intensity := range carbon.InitialRegionIntensity {
+			metric, ok := FindMetricWithLabelValues("karpenter_cloudprovider_grid_carbon_intensity", map[string]string{
+				"region": region,
+				"zone":   "",
+			})
+			Expect(ok).To(BeTrue())
+			Expect(aws.Float64Value(metric.GetGauge().Value)).To(Equal(intensity))
+		}
+	})
+	It("should not return intensity for an unknown region", func() {
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		_, ok := awsEnv.CarbonProvider.RegionIntensity("test-region-99")
+		Expect(ok).To(BeFalse())
+	})
+	It("should return static intensity data for all regions", func() {
+		provider := carbon.NewDefaultProvider(ctx, fakeClock, fake.DefaultRegion)
+		for region, intensity := range carbon.InitialRegionIntensity {
+			val, ok := provider.RegionIntensity(region)
+			Expect(ok).To(BeTrue())
+			Expect(val).To(Equal(intensity))
+		}
+	})
+	It("should fall back to the region intensity for zones without zonal data", func() {
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		intensity, ok := awsEnv.CarbonProvider.ZoneIntensity("test-zone-1a")
+		Expect(ok).To(BeTrue())
+		Expect(intensity).To(Equal(carbon.InitialRegionIntensity[fake.DefaultRegion]))
+	})
+	It("should fall back to us-east-1 intensity for regions without static data", func() {
+		provider := carbon.NewDefaultProvider(ctx, fakeClock, "test-region-99")
+		intensity, ok := provider.ZoneIntensity("test-zone-99a")
+		Expect(ok).To(BeTrue())
+		Expect(intensity).To(Equal(carbon.InitialRegionIntensity["us-east-1"]))
+	})
+	It("should return the PUE for a region", func() {
+		Expect(carbon.PUE(fake.DefaultRegion)).To(Equal(carbon.RegionPUE[fake.DefaultRegion]))
+		Expect(carbon.PUE("test-region-99")).To(Equal(carbon.DefaultPUE))
+	})
+	DescribeTable("should bucket intensity into carbon tiers",
+		func(intensity float64, tier string) {
+			Expect(carbon.Tier(intensity)).To(Equal(tier))
+		},
+		Entry("very low", 8.8, carbon.TierVeryLow),
+		Entry("low", 100.0, carbon.TierLow),
+		Entry("medium", 322.167, carbon.TierMedium),
+		Entry("high", 400.0, carbon.TierHigh),
+		Entry("very high", 708.2, carbon.TierVeryHigh),
+	)
+	Context("Live Intensity", func() {
+		var provider *carbon.DefaultProvider
+		var liveController *controllerscarbon.Controller
+		var forecast []carbon.Forecast
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+				CarbonIntensityURL:         lo.ToPtr(awsEnv.CarbonIntensityAPI.URL),
+				CarbonIntensityAuthHeader:  lo.ToPtr("auth-token: test-token"),
+				CarbonIntensityZoneMapping: lo.ToPtr("us-west-2=US-NW-PACW,test-zone-1a=US-NW-BPAT"),
+				CarbonIntensityTTL:         lo.ToPtr(time.Hour),
+			}))
+			provider = carbon.NewDefaultProvider(ctx, fakeClock, fake.DefaultRegion)
+			liveController = controllerscarbon.NewController(provider)
+			forecast = []carbon.Forecast{
+				{CarbonIntensity: 250, Datetime: fakeClock.Now().Add(time.Hour).UTC().Truncate(time.Second)},
+				{CarbonIntensity: 120, Datetime: fakeClock.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)},
+			}
+			awsEnv.CarbonIntensityAPI.SetIntensity(carbon.IntensityResponse{Zone: "US-NW-PACW", CarbonIntensity: 300, Forecast: forecast})
+			awsEnv.CarbonIntensityAPI.SetIntensity(carbon.IntensityResponse{Zone: "US-NW-BPAT", CarbonIntensity: 50})
+		})
+		It("should update region and zone intensity from the endpoint", func() {
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			intensity, ok := provider.RegionIntensity("us-west-2")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(BeNumerically("==", 300))
+			intensity, ok = provider.ZoneIntensity("test-zone-1a")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(BeNumerically("==", 50))
+			// zones without a mapping inherit the live region intensity
+			intensity, ok = provider.ZoneIntensity("test-zone-1b")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(BeNumerically("==", 300))
+			// regions without a mapping keep the static data
+			intensity, ok = provider.RegionIntensity("eu-north-1")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(Equal(carbon.InitialRegionIntensity["eu-north-1"]))
+		})
+		It("should store the intensity of wavelength zones as zone intensity", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+				CarbonIntensityURL:         lo.ToPtr(awsEnv.CarbonIntensityAPI.URL),
+				CarbonIntensityZoneMapping: lo.ToPtr("us-east-1-wl1-bos-wlz-1=US-NW-BPAT"),
+			}))
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			intensity, ok := provider.ZoneIntensity("us-east-1-wl1-bos-wlz-1")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(BeNumerically("==", 50))
+			intensity, ok = provider.RegionIntensity("us-east-1")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(Equal(carbon.InitialRegionIntensity["us-east-1"]))
+		})
+		It("should send the auth header and grid zone with each request", func() {
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			var zones []string
+			awsEnv.CarbonIntensityAPI.Requests.ForEach(func(req *fake.CarbonIntensityRequest) {
+				zones = append(zones, req.Zone)
+				Expect(req.Header.Get("auth-token")).To(Equal("test-token"))
+			})
+			Expect(zones).To(ConsistOf("US-NW-PACW", "US-NW-BPAT"))
+		})
+		It("should only request a grid zone once when several regions or zones map to it", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+				CarbonIntensityURL:         lo.ToPtr(awsEnv.CarbonIntensityAPI.URL),
+				CarbonIntensityZoneMapping: lo.ToPtr("us-west-2=US-NW-PACW,test-zone-1a=US-NW-PACW"),
+			}))
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			Expect(awsEnv.CarbonIntensityAPI.Requests.Len()).To(Equal(1))
+			intensity, ok := provider.ZoneIntensity("test-zone-1a")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(BeNumerically("==", 300))
+		})
+		It("should store the forecast", func() {
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			Expect(provider.Forecast("us-west-2")).To(Equal(forecast))
+			// zones without a forecast of their own use the region's forecast
+			Expect(provider.Forecast("test-zone-1b")).To(Equal(forecast))
+			Expect(provider.Forecast("test-zone-1a")).To(BeEmpty())
+		})
+		It("should fall back to the forecast of the zone's own region", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+				CarbonIntensityURL:         lo.ToPtr(awsEnv.CarbonIntensityAPI.URL),
+				CarbonIntensityZoneMapping: lo.ToPtr("us-west-2=US-NW-PACW,eu-north-1=SE"),
+			}))
+			seForecast := []carbon.Forecast{
+				{CarbonIntensity: 10, Datetime: fakeClock.Now().Add(time.Hour).UTC().Truncate(time.Second)},
+			}
+			awsEnv.CarbonIntensityAPI.SetIntensity(carbon.IntensityResponse{Zone: "SE", CarbonIntensity: 20, Forecast: seForecast})
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			Expect(provider.Forecast("eu-north-1a")).To(Equal(seForecast))
+			Expect(provider.Forecast("test-zone-1b")).To(Equal(forecast))
+		})
+		It("should find the earliest much lower intensity in the forecast", func() {
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			current, window, ok := provider.GreenWindow([]string{"test-zone-1b"}, fakeClock.Now(), fakeClock.Now().Add(4*time.Hour))
+			Expect(ok).To(BeTrue())
+			Expect(current).To(BeNumerically("==", 300))
+			Expect(window).To(Equal(forecast[1]))
+		})
+		It("should not find a much lower intensity beyond the deadline", func() {
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			_, _, ok := provider.GreenWindow([]string{"test-zone-1b"}, fakeClock.Now(), fakeClock.Now().Add(90*time.Minute))
+			Expect(ok).To(BeFalse())
+		})
+		It("should compare the forecast against the lowest current intensity of the zones", func() {
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			current, _, ok := provider.GreenWindow([]string{"test-zone-1a", "test-zone-1b"}, fakeClock.Now(), fakeClock.Now().Add(4*time.Hour))
+			Expect(ok).To(BeFalse())
+			Expect(current).To(BeNumerically("==", 50))
+		})
+		It("should increment the sequence number when intensity changes", func() {
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			seqNum := provider.SeqNum()
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			Expect(provider.SeqNum()).To(Equal(seqNum))
+			awsEnv.CarbonIntensityAPI.SetIntensity(carbon.IntensityResponse{Zone: "US-NW-BPAT", CarbonIntensity: 75})
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			Expect(provider.SeqNum()).To(BeNumerically(">", seqNum))
+		})
+		It("should retry transient failures", func() {
+			awsEnv.CarbonIntensityAPI.NextError.Set(fmt.Errorf("internal error"), fake.MaxCalls(2))
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			intensity, ok := provider.RegionIntensity("us-west-2")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(BeNumerically("==", 300))
+			Expect(awsEnv.CarbonIntensityAPI.Requests.Len()).To(Equal(4))
+		})
+		It("should not retry requests for unknown grid zones", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+				CarbonIntensityURL:         lo.ToPtr(awsEnv.CarbonIntensityAPI.URL),
+				CarbonIntensityZoneMapping: lo.ToPtr("us-west-2=US-UNKNOWN"),
+			}))
+			ExpectReconcileFailed(ctx, liveController, types.NamespacedName{})
+			Expect(awsEnv.CarbonIntensityAPI.Requests.Len()).To(Equal(1))
+		})
+		It("should retain live intensity when updates fail within the TTL", func() {
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			awsEnv.CarbonIntensityAPI.NextError.Set(fmt.Errorf("internal error"), fake.MaxCalls(0))
+			fakeClock.Step(30 * time.Minute)
+			ExpectReconcileFailed(ctx, liveController, types.NamespacedName{})
+			intensity, ok := provider.RegionIntensity("us-west-2")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(BeNumerically("==", 300))
+			intensity, ok = provider.ZoneIntensity("test-zone-1a")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(BeNumerically("==", 50))
+		})
+		It("should fall back to the static data once live intensity is stale", func() {
+			ExpectReconcileSucceeded(ctx, liveController, types.NamespacedName{})
+			awsEnv.CarbonIntensityAPI.NextError.Set(fmt.Errorf("internal error"), fake.MaxCalls(0))
+			fakeClock.Step(2 * time.Hour)
+			ExpectReconcileFailed(ctx, liveController, types.NamespacedName{})
+			intensity, ok := provider.RegionIntensity("us-west-2")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(Equal(carbon.InitialRegionIntensity["us-west-2"]))
+			intensity, ok = provider.ZoneIntensity("test-zone-1a")
+			Expect(ok).To(BeTrue())
+			Expect(intensity).To(Equal(carbon.InitialRegionIntensity["us-west-2"]))
+			Expect(provider.Forecast("us-west-2")).To(BeEmpty())
+		})
+	})
+	Context("Embodied Emissions", func() {
+		var instanceInfo map[string]*ec2.InstanceTypeInfo
+		BeforeEach(func() {
+			out, err := awsEnv.EC2API.DescribeInstanceTypesWithContext(ctx, &ec2.DescribeInstanceTypesInput{})
+			Expect(err).To(BeNil())
+			instanceInfo = lo.SliceToMap(out.InstanceTypes, func(info *ec2.InstanceTypeInfo) (string, *ec2.InstanceTypeInfo) {
+				return aws.StringValue(info.InstanceType), info
+			})
+		})
+		It("should attribute the whole host to an instance type that is its own host", func() {
+			embodied := awsEnv.CarbonProvider.EmbodiedEmissions(instanceInfo["m5.metal"], instanceInfo["m5.metal"])
+			Expect(embodied.KgCO2e).To(BeNumerically(">", 1000))
+			Expect(embodied.GramsPerHour).To(BeNumerically("~", embodied.KgCO2e*1000/carbon.HostLifespanHours))
+		})
+		It("should scale the host's emissions by the fraction of vCPUs used", func() {
+			host := awsEnv.CarbonProvider.EmbodiedEmissions(instanceInfo["m5.metal"], instanceInfo["m5.metal"])
+			embodied := awsEnv.CarbonProvider.EmbodiedEmissions(instanceInfo["m5.large"], instanceInfo["m5.metal"])
+			Expect(embodied.KgCO2e).To(BeNumerically("~", host.KgCO2e*2/96))
+		})
+		It("should use the instance type as its own host when no larger host is known", func() {
+			Expect(awsEnv.CarbonProvider.EmbodiedEmissions(instanceInfo["m5.large"], nil)).
+				To(Equal(awsEnv.CarbonProvider.EmbodiedEmissions(instanceInfo["m5.large"], instanceInfo["m5.large"])))
+			Expect(awsEnv.CarbonProvider.EmbodiedEmissions(instanceInfo["m5.xlarge"], instanceInfo["m5.large"])).
+				To(Equal(awsEnv.CarbonProvider.EmbodiedEmissions(instanceInfo["m5.xlarge"], instanceInfo["m5.xlarge"])))
+		})
+		It("should include the manufacturing emissions of GPUs", func() {
+			info := instanceInfo["p3.8xlarge"]
+			withoutGPUs := &ec2.InstanceTypeInfo{}
+			Expect(mergo.Merge(withoutGPUs, info)).To(Succeed())
+			withoutGPUs.GpuInfo = nil
+			gpus := float64(aws.Int64Value(info.GpuInfo.Gpus[0].Count))
+			Expect(awsEnv.CarbonProvider.EmbodiedEmissions(info, info).KgCO2e - awsEnv.CarbonProvider.EmbodiedEmissions(withoutGPUs, withoutGPUs).KgCO2e).
+				To(BeNumerically("~", gpus*150))
+		})
+	})
+})
@@ -0,0 +1,72 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/operator/controller"
+
+	"github.com/aws/karpenter-provider-aws/pkg/providers/commitment"
+)
+
+type Controller struct {
+	kubeClient         client.Client
+	commitmentProvider commitment.Provider
+}
+
+func NewController(kubeClient client.Client, commitmentProvider commitment.Provider) *Controller {
+	return &Controller{
+		kubeClient:         kubeClient,
+		commitmentProvider: commitmentProvider,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	nodeClaimList := &corev1beta1.NodeClaimList{}
+	if err := c.kubeClient.List(ctx, nodeClaimList); err != nil {
+		return reconcile.Result{}, fmt.Errorf("listing nodeclaims, %w", err)
+	}
+	if err := c.commitmentProvider.Update(ctx, lo.ToSlicePtr(nodeClaimList.Items)); err != nil {
+		return reconcile.Result{}, fmt.Errorf("updating pricing commitments, %w", err)
+	}
+	commitmentCount.Reset()
+	commitmentUsage.Reset()
+	for _, usage := range c.commitmentProvider.Usage() {
+		labels := prometheus.Labels{
+			commitmentLabel:     usage.Commitment.Name,
+			instanceFamilyLabel: usage.Commitment.InstanceFamily,
+		}
+		commitmentCount.With(labels).Set(float64(usage.Commitment.Count))
+		commitmentUsage.With(labels).Set(float64(usage.Used))
+	}
+	// usage changes as nodes are launched and consolidated, so it's recomputed far more frequently than pricing
+	return reconcile.Result{RequeueAfter: time.Minute}, nil
+}
+
+func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+	return controller.NewSingletonManagedBy(m).
+		Named("providers.commitment").
+		Complete(c)
+}
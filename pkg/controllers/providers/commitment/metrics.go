@@ -0,0 +1,59 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitment
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/karpenter/pkg/metrics"
+)
+
+const (
+	cloudProviderSubsystem = "cloudprovider"
+	commitmentLabel        = "commitment"
+	instanceFamilyLabel    = "instance_family"
+)
+
+var (
+	commitmentCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "pricing_commitment_count",
+			Help:      "Number of on-demand instances covered by a Savings Plan or Reserved Instance in the pricing commitments file, based on commitment and instance family.",
+		},
+		[]string{
+			commitmentLabel,
+			instanceFamilyLabel,
+		},
+	)
+	commitmentUsage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "pricing_commitment_usage",
+			Help:      "Number of on-demand NodeClaims using a Savings Plan or Reserved Instance in the pricing commitments file, based on commitment and instance family. Usage above the count means some of the NodeClaims are billed at the on-demand price.",
+		},
+		[]string{
+			commitmentLabel,
+			instanceFamilyLabel,
+		},
+	)
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(commitmentCount, commitmentUsage)
+}
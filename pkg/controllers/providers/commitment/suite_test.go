@@ -0,0 +1,147 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitment_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/operator/scheme"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	controllerscommitment "github.com/aws/karpenter-provider-aws/pkg/controllers/providers/commitment"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/commitment"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var stop context.CancelFunc
+var env *coretest.Environment
+var awsEnv *test.Environment
+var controller *controllerscommitment.Controller
+
+func TestAWS(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Commitment")
+}
+
+var _ = BeforeSuite(func() {
+	env = coretest.NewEnvironment(scheme.Scheme, coretest.WithCRDs(apis.CRDs...))
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options())
+	ctx, stop = context.WithCancel(ctx)
+	awsEnv = test.NewEnvironment(ctx, env)
+	controller = controllerscommitment.NewController(env.Client, awsEnv.CommitmentProvider)
+})
+
+var _ = AfterSuite(func() {
+	stop()
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options())
+
+	awsEnv.Reset()
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = Describe("Commitment", func() {
+	var nodePool *corev1beta1.NodePool
+	nodeClaim := func(instanceType, capacityType string) *corev1beta1.NodeClaim {
+		return coretest.NodeClaim(corev1beta1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					corev1beta1.NodePoolLabelKey:     nodePool.Name,
+					corev1beta1.CapacityTypeLabelKey: capacityType,
+					v1.LabelInstanceTypeStable:       instanceType,
+					v1.LabelTopologyRegion:           fake.DefaultRegion,
+					v1.LabelTopologyZone:             "test-zone-1a",
+				},
+			},
+		})
+	}
+	BeforeEach(func() {
+		nodePool = coretest.NodePool()
+		file := filepath.Join(GinkgoT().TempDir(), "commitments.yaml")
+		Expect(os.WriteFile(file, []byte(`
+commitments:
+- name: reserved-instance
+  instanceFamily: m5
+  sizes: [large]
+  count: 2
+  effectiveRate: 0.6
+- name: compute-savings-plan
+  instanceFamily: c5
+  count: 3
+  effectivePrice: 0.05
+`), 0o600)).To(Succeed())
+		ctx = options.ToContext(ctx, test.Options(test.OptionsFields{PricingCommitmentsFile: lo.ToPtr(file)}))
+	})
+	It("should requeue to recompute usage", func() {
+		result := ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+	})
+	It("should track usage from the on-demand nodeclaims", func() {
+		ExpectApplied(ctx, env.Client, nodePool,
+			nodeClaim("m5.large", corev1beta1.CapacityTypeOnDemand),
+			nodeClaim("m5.large", corev1beta1.CapacityTypeSpot),
+			nodeClaim("m5.xlarge", corev1beta1.CapacityTypeOnDemand),
+			nodeClaim("c5.large", corev1beta1.CapacityTypeOnDemand),
+			nodeClaim("c5.xlarge", corev1beta1.CapacityTypeOnDemand),
+		)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		usage := lo.SliceToMap(awsEnv.CommitmentProvider.Usage(), func(u commitment.Usage) (string, int) { return u.Commitment.Name, u.Used })
+		Expect(usage).To(Equal(map[string]int{"reserved-instance": 1, "compute-savings-plan": 2}))
+	})
+	It("should expose commitment count and usage metrics", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim("m5.large", corev1beta1.CapacityTypeOnDemand))
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		labels := map[string]string{"commitment": "reserved-instance", "instance_family": "m5"}
+		metric, ok := FindMetricWithLabelValues("karpenter_cloudprovider_pricing_commitment_count", labels)
+		Expect(ok).To(BeTrue())
+		Expect(aws.Float64Value(metric.GetGauge().Value)).To(BeNumerically("==", 2))
+		metric, ok = FindMetricWithLabelValues("karpenter_cloudprovider_pricing_commitment_usage", labels)
+		Expect(ok).To(BeTrue())
+		Expect(aws.Float64Value(metric.GetGauge().Value)).To(BeNumerically("==", 1))
+	})
+	It("should fail to reconcile when the commitments file is missing", func() {
+		ctx = options.ToContext(ctx, test.Options(test.OptionsFields{PricingCommitmentsFile: lo.ToPtr(filepath.Join(GinkgoT().TempDir(), "missing.yaml"))}))
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+	})
+})
@@ -0,0 +1,108 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overrides
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/system"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
+)
+
+// Controller applies the price overrides in the pricing overrides ConfigMap whenever it changes. Overrides that fail
+// validation are rejected as a whole, and the previously active overrides stay in effect until they're fixed.
+type Controller struct {
+	kubeClient client.Reader
+	overrides  *pricing.Overrides
+}
+
+func NewController(kubeClient client.Reader, overrides *pricing.Overrides) *Controller {
+	return &Controller{
+		kubeClient: kubeClient,
+		overrides:  overrides,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	configMap := &v1.ConfigMap{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Namespace: system.Namespace(), Name: options.FromContext(ctx).PricingOverridesConfigMap}, configMap); err != nil {
+		if !errors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("getting pricing overrides configmap, %w", err)
+		}
+		// offerings are priced at list price while the configmap doesn't exist
+	}
+	overrides, err := pricing.ParseOverrides(configMap.Data)
+	if err != nil {
+		overridesValid.Set(0)
+		return reconcile.Result{}, fmt.Errorf("validating pricing overrides configmap, %w", err)
+	}
+	overridesValid.Set(1)
+	c.overrides.Set(ctx, overrides)
+	override.Reset()
+	for _, o := range overrides {
+		labels := prometheus.Labels{
+			instanceTypeLabel:    o.InstanceType,
+			operatingSystemLabel: o.OperatingSystem,
+			zoneLabel:            o.Zone,
+			capacityTypeLabel:    o.CapacityType,
+		}
+		if o.Price != nil {
+			override.With(lo.Assign(labels, prometheus.Labels{typeLabel: "price"})).Set(*o.Price)
+		} else {
+			override.With(lo.Assign(labels, prometheus.Labels{typeLabel: "multiplier"})).Set(lo.FromPtr(o.Multiplier))
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
+	// Karpenter is only allowed to read the configmaps in its own namespace, so the configmap is watched and read through
+	// a cache that's scoped to it rather than the manager's cluster-wide cache
+	configMapCache, err := cache.New(m.GetConfig(), cache.Options{
+		Scheme:               m.GetScheme(),
+		Mapper:               m.GetRESTMapper(),
+		DefaultNamespaces:    map[string]cache.Config{system.Namespace(): {}},
+		DefaultFieldSelector: fields.OneTermEqualSelector("metadata.name", options.FromContext(ctx).PricingOverridesConfigMap),
+	})
+	if err != nil {
+		return fmt.Errorf("creating pricing overrides configmap cache, %w", err)
+	}
+	if err := m.Add(configMapCache); err != nil {
+		return fmt.Errorf("adding pricing overrides configmap cache, %w", err)
+	}
+	c.kubeClient = configMapCache
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("providers.pricing.overrides").
+		WatchesRawSource(source.Kind(configMapCache, client.Object(&v1.ConfigMap{}), handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(o)}}
+		}))).
+		Complete(c)
+}
@@ -0,0 +1,61 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overrides
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/karpenter/pkg/metrics"
+)
+
+const (
+	cloudProviderSubsystem = "cloudprovider"
+	instanceTypeLabel      = "instance_type"
+	operatingSystemLabel   = "operating_system"
+	zoneLabel              = "zone"
+	capacityTypeLabel      = "capacity_type"
+	typeLabel              = "type"
+)
+
+var (
+	override = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "pricing_override",
+			Help:      "Active price overrides from the pricing overrides ConfigMap, based on instance type, operating system, zone and capacity type. Empty labels select every value. The value is the multiplier or the hourly price, depending on the type.",
+		},
+		[]string{
+			instanceTypeLabel,
+			operatingSystemLabel,
+			zoneLabel,
+			capacityTypeLabel,
+			typeLabel,
+		},
+	)
+	overridesValid = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "pricing_overrides_valid",
+			Help:      "Whether the pricing overrides ConfigMap passed validation when it was last read. The value is 1 if its overrides are active and 0 if they were rejected, in which case the previously active overrides stay in effect.",
+		},
+	)
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(override, overridesValid)
+}
@@ -0,0 +1,270 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overrides_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	awspricing "github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/system"
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/operator/scheme"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	controllerspricingoverrides "github.com/aws/karpenter-provider-aws/pkg/controllers/providers/pricing/overrides"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var stop context.CancelFunc
+var env *coretest.Environment
+var awsEnv *test.Environment
+var controller *controllerspricingoverrides.Controller
+
+func TestAWS(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PricingOverrides")
+}
+
+var _ = BeforeSuite(func() {
+	env = coretest.NewEnvironment(scheme.Scheme, coretest.WithCRDs(apis.CRDs...))
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options())
+	ctx, stop = context.WithCancel(ctx)
+	awsEnv = test.NewEnvironment(ctx, env)
+	controller = controllerspricingoverrides.NewController(env.Client, awsEnv.PricingOverrides)
+})
+
+var _ = AfterSuite(func() {
+	stop()
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options(test.OptionsFields{PricingOverridesConfigMap: lo.ToPtr("pricing-overrides")}))
+
+	awsEnv.Reset()
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = Describe("PricingOverrides", func() {
+	var configMap *v1.ConfigMap
+	BeforeEach(func() {
+		configMap = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "pricing-overrides", Namespace: system.Namespace()},
+			Data: map[string]string{
+				pricing.OverridesKey: `
+- multiplier: 0.9
+- instanceType: m5.large
+  capacityType: on-demand
+  price: 0.05
+- instanceType: c5.large
+  zone: test-zone-1a
+  capacityType: spot
+  multiplier: 1.2
+`,
+			},
+		}
+	})
+	AfterEach(func() {
+		ExpectDeleted(ctx, env.Client, configMap)
+	})
+	It("should apply the overrides in the configmap", func() {
+		listPrice, ok := awsEnv.PricingProvider.OnDemandPrice("m5.xlarge")
+		Expect(ok).To(BeTrue())
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		Expect(awsEnv.PricingOverrides.List()).To(HaveLen(3))
+
+		price, ok := awsEnv.PricingProvider.OnDemandPrice("m5.large")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 0.05))
+		price, ok = awsEnv.PricingProvider.OnDemandPrice("m5.xlarge")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("~", listPrice*0.9))
+	})
+	It("should apply the most specific override", func() {
+		now := time.Now()
+		awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
+			SpotPriceHistory: []*ec2.SpotPrice{
+				{
+					AvailabilityZone: aws.String("test-zone-1a"),
+					InstanceType:     aws.String("c5.large"),
+					SpotPrice:        aws.String("0.10"),
+					Timestamp:        &now,
+				},
+				{
+					AvailabilityZone: aws.String("test-zone-1b"),
+					InstanceType:     aws.String("c5.large"),
+					SpotPrice:        aws.String("0.20"),
+					Timestamp:        &now,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+
+		Expect(awsEnv.PricingProvider.UpdateSpotPricing(ctx)).To(Succeed())
+		price, ok := awsEnv.PricingProvider.SpotPrice("c5.large", "test-zone-1a")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("~", 0.1*1.2))
+		price, ok = awsEnv.PricingProvider.SpotPrice("c5.large", "test-zone-1b")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("~", 0.2*0.9))
+	})
+	It("should only apply overrides to offerings of the operating system they select", func() {
+		awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+			PriceList: []aws.JSONValue{
+				fake.NewOnDemandPriceWithOperatingSystem("m5.large", 0.096, "Linux"),
+				fake.NewOnDemandPriceWithOperatingSystem("m5.large", 0.188, "Windows"),
+			},
+		})
+		Expect(awsEnv.PricingProvider.UpdateOnDemandPricing(ctx)).To(Succeed())
+		configMap.Data[pricing.OverridesKey] = `
+- instanceType: m5.large
+  operatingSystem: linux
+  price: 0.05
+- instanceType: m5.large
+  operatingSystem: windows
+  multiplier: 0.9
+`
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+
+		price, ok := awsEnv.PricingProvider.OnDemandPriceForOS("m5.large", string(v1.Linux))
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 0.05))
+		price, ok = awsEnv.PricingProvider.OnDemandPriceForOS("m5.large", string(v1.Windows))
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("~", 0.188*0.9))
+	})
+	It("should bump the overrides sequence number when the overrides change", func() {
+		seqNum := awsEnv.PricingProvider.OverridesSeqNum()
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		Expect(awsEnv.PricingProvider.OverridesSeqNum()).To(BeNumerically(">", seqNum))
+
+		seqNum = awsEnv.PricingProvider.OverridesSeqNum()
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		Expect(awsEnv.PricingProvider.OverridesSeqNum()).To(Equal(seqNum))
+	})
+	It("should clear the overrides when the configmap is deleted", func() {
+		listPrice, ok := awsEnv.PricingProvider.OnDemandPrice("m5.large")
+		Expect(ok).To(BeTrue())
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		Expect(awsEnv.PricingOverrides.List()).To(HaveLen(3))
+
+		ExpectDeleted(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		Expect(awsEnv.PricingOverrides.List()).To(BeEmpty())
+		price, ok := awsEnv.PricingProvider.OnDemandPrice("m5.large")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", listPrice))
+	})
+	It("should keep the previous overrides when the configmap is invalid", func() {
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+
+		configMap.Data[pricing.OverridesKey] = `
+- instanceType: m5.large
+  multiplier: 0.8
+  price: 0.05
+`
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+		Expect(awsEnv.PricingOverrides.List()).To(HaveLen(3))
+	})
+	DescribeTable("should reject invalid overrides",
+		func(overrides string) {
+			configMap.Data[pricing.OverridesKey] = overrides
+			ExpectApplied(ctx, env.Client, configMap)
+			ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+		},
+		Entry("without a multiplier or price", "- instanceType: m5.large"),
+		Entry("with a negative multiplier", "- instanceType: m5.large\n  multiplier: -0.5"),
+		Entry("with a zero price", "- instanceType: m5.large\n  price: 0"),
+		Entry("with an unknown capacity type", "- capacityType: reserved\n  multiplier: 0.5"),
+		Entry("with an unknown operating system", "- operatingSystem: darwin\n  multiplier: 0.5"),
+		Entry("with a zone for on-demand offerings", "- zone: test-zone-1a\n  capacityType: on-demand\n  multiplier: 0.5"),
+		Entry("with an unknown field", "- instanceFamily: m5\n  multiplier: 0.5"),
+		Entry("with duplicate selectors", "- instanceType: m5.large\n  multiplier: 0.5\n- instanceType: m5.large\n  price: 0.05"),
+	)
+	It("should allow overrides that only differ by operating system", func() {
+		configMap.Data[pricing.OverridesKey] = "- instanceType: m5.large\n  operatingSystem: linux\n  price: 0.05\n- instanceType: m5.large\n  operatingSystem: windows\n  price: 0.1"
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		Expect(awsEnv.PricingOverrides.List()).To(HaveLen(2))
+	})
+	It("should report whether the configmap is valid", func() {
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		metric, ok := FindMetricWithLabelValues("karpenter_cloudprovider_pricing_overrides_valid", map[string]string{})
+		Expect(ok).To(BeTrue())
+		Expect(aws.Float64Value(metric.GetGauge().Value)).To(BeNumerically("==", 1))
+
+		configMap.Data[pricing.OverridesKey] = "- instanceType: m5.large"
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+		metric, ok = FindMetricWithLabelValues("karpenter_cloudprovider_pricing_overrides_valid", map[string]string{})
+		Expect(ok).To(BeTrue())
+		Expect(aws.Float64Value(metric.GetGauge().Value)).To(BeNumerically("==", 0))
+	})
+	It("should expose the active overrides as metrics", func() {
+		ExpectApplied(ctx, env.Client, configMap)
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		metric, ok := FindMetricWithLabelValues("karpenter_cloudprovider_pricing_override", map[string]string{
+			"instance_type":    "m5.large",
+			"operating_system": "",
+			"zone":             "",
+			"capacity_type":    corev1beta1.CapacityTypeOnDemand,
+			"type":             "price",
+		})
+		Expect(ok).To(BeTrue())
+		Expect(aws.Float64Value(metric.GetGauge().Value)).To(BeNumerically("==", 0.05))
+		metric, ok = FindMetricWithLabelValues("karpenter_cloudprovider_pricing_override", map[string]string{
+			"instance_type":    "",
+			"operating_system": "",
+			"zone":             "",
+			"capacity_type":    "",
+			"type":             "multiplier",
+		})
+		Expect(ok).To(BeTrue())
+		Expect(aws.Float64Value(metric.GetGauge().Value)).To(BeNumerically("==", 0.9))
+	})
+})
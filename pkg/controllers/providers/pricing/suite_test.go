@@ -24,6 +24,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	awspricing "github.com/aws/aws-sdk-go/service/pricing"
 	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/operator/scheme"
@@ -84,7 +85,7 @@ var _ = Describe("Pricing", func() {
 		"should return correct static data for all partitions",
 		func(staticPricing map[string]map[string]float64) {
 			for region, prices := range staticPricing {
-				provider := pricing.NewDefaultProvider(ctx, awsEnv.PricingAPI, awsEnv.EC2API, region)
+				provider := pricing.NewDefaultProvider(ctx, awsEnv.PricingAPI, awsEnv.EC2API, region, pricing.NewOverrides())
 				for instance, price := range prices {
 					val, ok := provider.OnDemandPrice(instance)
 					Expect(ok).To(BeTrue())
@@ -129,6 +130,86 @@ var _ = Describe("Pricing", func() {
 		Expect(ok).To(BeTrue())
 		Expect(price).To(BeNumerically("==", 1.23))
 	})
+	It("should update windows on-demand pricing with response from the pricing API", func() {
+		awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+			PriceList: []aws.JSONValue{
+				fake.NewOnDemandPriceWithOperatingSystem("c98.large", 1.20, "Linux"),
+				fake.NewOnDemandPriceWithOperatingSystem("c98.large", 2.04, "Windows"),
+				fake.NewOnDemandPriceWithOperatingSystem("c99.large", 1.23, "Linux"),
+			},
+		})
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+
+		price, ok := awsEnv.PricingProvider.OnDemandPrice("c98.large")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 1.20))
+
+		price, ok = awsEnv.PricingProvider.OnDemandPriceForOS("c98.large", string(v1.Windows))
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 2.04))
+
+		price, ok = awsEnv.PricingProvider.OnDemandPriceForOS("c98.large", string(v1.Linux))
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 1.20))
+	})
+	It("should fall back to the linux on-demand price for instance types without a windows price", func() {
+		awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+			PriceList: []aws.JSONValue{
+				fake.NewOnDemandPriceWithOperatingSystem("c98.large", 1.20, "Linux"),
+				fake.NewOnDemandPriceWithOperatingSystem("c98.large", 2.04, "Windows"),
+				fake.NewOnDemandPriceWithOperatingSystem("c99.large", 1.23, "Linux"),
+			},
+		})
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+
+		price, ok := awsEnv.PricingProvider.OnDemandPriceForOS("c99.large", string(v1.Windows))
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 1.23))
+	})
+	It("should return static linux on-demand data for windows before pricing is updated", func() {
+		linuxPrice, ok := awsEnv.PricingProvider.OnDemandPrice("c5.large")
+		Expect(ok).To(BeTrue())
+		price, ok := awsEnv.PricingProvider.OnDemandPriceForOS("c5.large", string(v1.Windows))
+		Expect(ok).To(BeTrue())
+		Expect(price).To(Equal(linuxPrice))
+	})
+	It("should keep linux on-demand pricing when the windows price list is empty", func() {
+		awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+			PriceList: []aws.JSONValue{
+				fake.NewOnDemandPriceWithOperatingSystem("c98.large", 1.20, "Linux"),
+			},
+		})
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+
+		price, ok := awsEnv.PricingProvider.OnDemandPrice("c98.large")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 1.20))
+		price, ok = awsEnv.PricingProvider.OnDemandPriceForOS("c98.large", string(v1.Windows))
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 1.20))
+	})
+	It("should query the windows price list for license included prices", func() {
+		awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+			PriceList: []aws.JSONValue{
+				fake.NewOnDemandPrice("c98.large", 1.20),
+			},
+		})
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+
+		filters := map[string][]map[string]string{}
+		awsEnv.PricingAPI.GetProductsInput.ForEach(func(input *awspricing.GetProductsInput) {
+			values := lo.SliceToMap(input.Filters, func(f *awspricing.Filter) (string, string) { return aws.StringValue(f.Field), aws.StringValue(f.Value) })
+			filters[values["operatingSystem"]] = append(filters[values["operatingSystem"]], values)
+		})
+		Expect(filters).To(HaveKey("Linux"))
+		Expect(filters).To(HaveKey("Windows"))
+		Expect(filters["Linux"]).To(HaveLen(2))
+		Expect(filters["Windows"]).To(HaveLen(2))
+		for _, values := range filters["Windows"] {
+			Expect(values).To(HaveKeyWithValue("licenseModel", "License included"))
+			Expect(values).To(HaveKeyWithValue("preInstalledSw", "NA"))
+		}
+	})
 	It("should update spot pricing with response from the pricing API", func() {
 		now := time.Now()
 		awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
@@ -258,6 +339,56 @@ var _ = Describe("Pricing", func() {
 		Expect(lo.Map(inp.ProductDescriptions, func(x *string, _ int) string { return *x })).
 			To(ContainElements("Linux/UNIX", "Linux/UNIX (Amazon VPC)"))
 	})
+	It("should update windows spot pricing from `Windows` and `Windows (Amazon VPC)` spot price history", func() {
+		now := time.Now()
+		awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
+			SpotPriceHistory: []*ec2.SpotPrice{
+				{
+					AvailabilityZone:   aws.String("test-zone-1a"),
+					InstanceType:       aws.String("c99.large"),
+					ProductDescription: aws.String("Linux/UNIX (Amazon VPC)"),
+					SpotPrice:          aws.String("1.23"),
+					Timestamp:          &now,
+				},
+				{
+					AvailabilityZone:   aws.String("test-zone-1a"),
+					InstanceType:       aws.String("c99.large"),
+					ProductDescription: aws.String("Windows (Amazon VPC)"),
+					SpotPrice:          aws.String("2.46"),
+					Timestamp:          &now,
+				},
+				{
+					AvailabilityZone:   aws.String("test-zone-1b"),
+					InstanceType:       aws.String("c99.large"),
+					ProductDescription: aws.String("Linux/UNIX"),
+					SpotPrice:          aws.String("1.50"),
+					Timestamp:          &now,
+				},
+			},
+		})
+		awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+			PriceList: []aws.JSONValue{
+				fake.NewOnDemandPrice("c99.large", 1.23),
+			},
+		})
+		ExpectReconcileSucceeded(ctx, controller, types.NamespacedName{})
+		inp := awsEnv.EC2API.DescribeSpotPriceHistoryInput.Clone()
+		Expect(lo.Map(inp.ProductDescriptions, func(x *string, _ int) string { return *x })).
+			To(ContainElements("Windows", "Windows (Amazon VPC)"))
+
+		price, ok := awsEnv.PricingProvider.SpotPrice("c99.large", "test-zone-1a")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 1.23))
+
+		price, ok = awsEnv.PricingProvider.SpotPriceForOS("c99.large", string(v1.Windows), "test-zone-1a")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 2.46))
+
+		// zones without a windows spot price fall back to the linux spot price
+		price, ok = awsEnv.PricingProvider.SpotPriceForOS("c99.large", string(v1.Windows), "test-zone-1b")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 1.50))
+	})
 	It("should return static on-demand data when in isolated-vpc", func() {
 		ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
 			IsolatedVPC: lo.ToPtr(true),
@@ -298,7 +429,7 @@ var _ = Describe("Pricing", func() {
 		Expect(price).To(BeNumerically("==", 1.10))
 	})
 	It("should update on-demand pricing with response from the pricing API when in the CN partition", func() {
-		tmpPricingProvider := pricing.NewDefaultProvider(ctx, awsEnv.PricingAPI, awsEnv.EC2API, "cn-anywhere-1")
+		tmpPricingProvider := pricing.NewDefaultProvider(ctx, awsEnv.PricingAPI, awsEnv.EC2API, "cn-anywhere-1", pricing.NewOverrides())
 		tmpController := controllerspricing.NewController(tmpPricingProvider)
 
 		now := time.Now()
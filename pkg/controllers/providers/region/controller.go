@@ -0,0 +1,51 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package region
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/karpenter/pkg/operator/controller"
+
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
+)
+
+type Controller struct {
+	regionProvider region.Provider
+}
+
+func NewController(regionProvider region.Provider) *Controller {
+	return &Controller{
+		regionProvider: regionProvider,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	if err := c.regionProvider.Update(ctx); err != nil {
+		return reconcile.Result{}, fmt.Errorf("updating additional regions, %w", err)
+	}
+	return reconcile.Result{RequeueAfter: 12 * time.Hour}, nil
+}
+
+func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+	// Includes a default exponential failure rate limiter of base: time.Millisecond, and max: 1000*time.Second
+	return controller.NewSingletonManagedBy(m).
+		Named("providers.region").
+		Complete(c)
+}
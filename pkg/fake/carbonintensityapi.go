@@ -0,0 +1,89 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+)
+
+// CarbonIntensityAPI is a local stand-in for a live carbon intensity endpoint, such as Electricity Maps, that serves
+// the intensity set for each grid zone
+type CarbonIntensityAPI struct {
+	*httptest.Server
+	CarbonIntensityBehavior
+
+	mu          sync.RWMutex
+	intensities map[string]*carbon.IntensityResponse
+}
+
+// CarbonIntensityBehavior must be reset between tests otherwise tests will
+// pollute each other.
+type CarbonIntensityBehavior struct {
+	// NextError fails requests with an internal server error
+	NextError AtomicError
+	Requests  AtomicPtrSlice[CarbonIntensityRequest]
+}
+
+type CarbonIntensityRequest struct {
+	Zone   string
+	Header http.Header
+}
+
+func NewCarbonIntensityAPI() *CarbonIntensityAPI {
+	api := &CarbonIntensityAPI{intensities: map[string]*carbon.IntensityResponse{}}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.serveHTTP))
+	return api
+}
+
+func (c *CarbonIntensityAPI) Reset() {
+	c.NextError.Reset()
+	c.Requests.Reset()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.intensities = map[string]*carbon.IntensityResponse{}
+}
+
+// SetIntensity sets the response served for a grid zone
+func (c *CarbonIntensityAPI) SetIntensity(out carbon.IntensityResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.intensities[out.Zone] = &out
+}
+
+func (c *CarbonIntensityAPI) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	zone := r.URL.Query().Get("zone")
+	c.Requests.Add(&CarbonIntensityRequest{Zone: zone, Header: r.Header.Clone()})
+	if err := c.NextError.Get(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	c.mu.RLock()
+	out, ok := c.intensities[zone]
+	c.mu.RUnlock()
+	if !ok {
+		// fail if the test doesn't provide data for the zone, in the same way that the real API rejects unknown zones
+		http.Error(w, "zone not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
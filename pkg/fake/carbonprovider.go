@@ -0,0 +1,129 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/utils"
+)
+
+// CarbonProvider serves the intensity and forecast set for each zone, so that tests can control the intensity that
+// offerings are weighted, refused and drifted by without a carbon intensity endpoint. Zones without a set intensity
+// are served by the wrapped DefaultProvider.
+type CarbonProvider struct {
+	*carbon.DefaultProvider
+
+	mu            sync.RWMutex
+	zoneIntensity map[string]float64
+	forecast      map[string][]carbon.Forecast
+	lastUpdated   map[string]time.Time
+	// seqNum is added to the wrapped provider's so that consumers see the set intensity as a change
+	seqNum uint64
+}
+
+func NewCarbonProvider(provider *carbon.DefaultProvider) *CarbonProvider {
+	return &CarbonProvider{
+		DefaultProvider: provider,
+		zoneIntensity:   map[string]float64{},
+		forecast:        map[string][]carbon.Forecast{},
+		lastUpdated:     map[string]time.Time{},
+	}
+}
+
+// Reset clears the set intensity and forecasts and resets the wrapped provider
+func (c *CarbonProvider) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.zoneIntensity = map[string]float64{}
+	c.forecast = map[string][]carbon.Forecast{}
+	c.lastUpdated = map[string]time.Time{}
+	atomic.AddUint64(&c.seqNum, 1)
+	c.DefaultProvider.Reset()
+}
+
+// SetZoneIntensity sets the intensity served for a zone, as if it had just been updated from live data
+func (c *CarbonProvider) SetZoneIntensity(zone string, intensity float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.zoneIntensity[zone] = intensity
+	c.lastUpdated[zone] = time.Now()
+	atomic.AddUint64(&c.seqNum, 1)
+}
+
+// SetForecast sets the forecast served for a zone
+func (c *CarbonProvider) SetForecast(zone string, forecast []carbon.Forecast) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forecast[zone] = forecast
+	atomic.AddUint64(&c.seqNum, 1)
+}
+
+func (c *CarbonProvider) ZoneIntensity(zone string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if intensity, ok := c.zoneIntensity[zone]; ok {
+		return intensity, true
+	}
+	return c.DefaultProvider.ZoneIntensity(zone)
+}
+
+func (c *CarbonProvider) ZoneDataSource(zone string) (carbon.DataSource, time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if lastUpdated, ok := c.lastUpdated[zone]; ok {
+		return carbon.DataSourceLive, lastUpdated
+	}
+	return c.DefaultProvider.ZoneDataSource(zone)
+}
+
+func (c *CarbonProvider) Intensities() []carbon.Intensity {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	intensities := lo.Reject(c.DefaultProvider.Intensities(), func(i carbon.Intensity, _ int) bool {
+		_, ok := c.zoneIntensity[i.Zone]
+		return ok
+	})
+	for zone, intensity := range c.zoneIntensity {
+		region, ok := utils.RegionForZone(zone)
+		if !ok {
+			region = DefaultRegion
+		}
+		intensities = append(intensities, carbon.Intensity{Region: region, Zone: zone, GramsPerKWh: intensity})
+	}
+	return intensities
+}
+
+func (c *CarbonProvider) Forecast(zone string) []carbon.Forecast {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if forecast, ok := c.forecast[zone]; ok {
+		return forecast
+	}
+	return c.DefaultProvider.Forecast(zone)
+}
+
+func (c *CarbonProvider) GreenWindow(zones []string, now, deadline time.Time) (float64, carbon.Forecast, bool) {
+	return carbon.FindGreenWindow(c, zones, now, deadline)
+}
+
+func (c *CarbonProvider) SeqNum() uint64 {
+	return c.DefaultProvider.SeqNum() + atomic.LoadUint64(&c.seqNum)
+}
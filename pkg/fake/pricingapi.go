@@ -22,6 +22,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/pricing"
 	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
+	"github.com/samber/lo"
 )
 
 type PricingAPI struct {
@@ -31,19 +32,31 @@ type PricingAPI struct {
 type PricingBehavior struct {
 	NextError         AtomicError
 	GetProductsOutput AtomicPtr[pricing.GetProductsOutput]
+	GetProductsInput  AtomicPtrSlice[pricing.GetProductsInput]
 }
 
 func (p *PricingAPI) Reset() {
 	p.NextError.Reset()
 	p.GetProductsOutput.Reset()
+	p.GetProductsInput.Reset()
 }
 
-func (p *PricingAPI) GetProductsPagesWithContext(_ aws.Context, _ *pricing.GetProductsInput, fn func(*pricing.GetProductsOutput, bool) bool, _ ...request.Option) error {
+func (p *PricingAPI) GetProductsPagesWithContext(_ aws.Context, input *pricing.GetProductsInput, fn func(*pricing.GetProductsOutput, bool) bool, _ ...request.Option) error {
+	p.GetProductsInput.Add(input)
 	if !p.NextError.IsNil() {
 		return p.NextError.Get()
 	}
 	if !p.GetProductsOutput.IsNil() {
-		fn(p.GetProductsOutput.Clone(), false)
+		output := p.GetProductsOutput.Clone()
+		// prices with an operating system are only returned when it matches the filter, like the pricing API
+		if filter, ok := lo.Find(input.Filters, func(f *pricing.Filter) bool { return aws.StringValue(f.Field) == "operatingSystem" }); ok {
+			output.PriceList = lo.Filter(output.PriceList, func(price aws.JSONValue, _ int) bool {
+				attributes := price["product"].(map[string]interface{})["attributes"].(map[string]interface{})
+				operatingSystem, ok := attributes["operatingSystem"]
+				return !ok || operatingSystem == aws.StringValue(filter.Value)
+			})
+		}
+		fn(output, false)
 		return nil
 	}
 	// fail if the test doesn't provide specific data which causes our pricing provider to use its static price list
@@ -54,6 +67,13 @@ func NewOnDemandPrice(instanceType string, price float64) aws.JSONValue {
 	return NewOnDemandPriceWithCurrency(instanceType, price, "USD")
 }
 
+// NewOnDemandPriceWithOperatingSystem returns a price that's only returned for the operating system, such as Windows
+func NewOnDemandPriceWithOperatingSystem(instanceType string, price float64, operatingSystem string) aws.JSONValue {
+	p := NewOnDemandPrice(instanceType, price)
+	p["product"].(map[string]interface{})["attributes"].(map[string]interface{})["operatingSystem"] = operatingSystem
+	return p
+}
+
 func NewOnDemandPriceWithCurrency(instanceType string, price float64, currency string) aws.JSONValue {
 	return aws.JSONValue{
 		"product": map[string]interface{}{
@@ -54,11 +54,14 @@ import (
 	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/commitment"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/version"
@@ -83,9 +86,13 @@ type Operator struct {
 	AMIResolver               *amifamily.Resolver
 	LaunchTemplateProvider    launchtemplate.Provider
 	PricingProvider           pricing.Provider
+	PricingOverrides          *pricing.Overrides
+	CarbonProvider            carbon.Provider
+	CommitmentProvider        commitment.Provider
 	VersionProvider           version.Provider
 	InstanceTypesProvider     instancetype.Provider
 	InstanceProvider          instance.Provider
+	RegionProvider            region.Provider
 }
 
 func NewOperator(ctx context.Context, operator *operator.Operator) (context.Context, *Operator) {
@@ -137,15 +144,20 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 	subnetProvider := subnet.NewDefaultProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval), cache.New(awscache.AvailableIPAddressTTL, awscache.DefaultCleanupInterval), cache.New(awscache.AssociatePublicIPAddressTTL, awscache.DefaultCleanupInterval))
 	securityGroupProvider := securitygroup.NewDefaultProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
 	instanceProfileProvider := instanceprofile.NewDefaultProvider(*sess.Config.Region, iam.New(sess), cache.New(awscache.InstanceProfileTTL, awscache.DefaultCleanupInterval))
+	pricingOverrides := pricing.NewOverrides()
 	pricingProvider := pricing.NewDefaultProvider(
 		ctx,
 		pricing.NewAPI(sess, *sess.Config.Region),
 		ec2api,
 		*sess.Config.Region,
+		pricingOverrides,
 	)
+	carbonProvider := carbon.NewDefaultProvider(ctx, operator.Clock, *sess.Config.Region)
+	commitmentProvider := commitment.NewDefaultProvider()
 	versionProvider := version.NewDefaultProvider(operator.KubernetesInterface, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
 	amiProvider := amifamily.NewDefaultProvider(versionProvider, ssm.New(sess), ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
 	amiResolver := amifamily.NewResolver(amiProvider)
+	caBundle := lo.Must(GetCABundle(ctx, operator.GetConfig()))
 	launchTemplateProvider := launchtemplate.NewDefaultProvider(
 		ctx,
 		cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval),
@@ -154,7 +166,7 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		amiResolver,
 		securityGroupProvider,
 		subnetProvider,
-		lo.Must(GetCABundle(ctx, operator.GetConfig())),
+		caBundle,
 		operator.Elected(),
 		kubeDNSIP,
 		clusterEndpoint,
@@ -166,6 +178,8 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		subnetProvider,
 		unavailableOfferingsCache,
 		pricingProvider,
+		carbonProvider,
+		commitmentProvider,
 	)
 	instanceProvider := instance.NewDefaultProvider(
 		ctx,
@@ -176,6 +190,67 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		subnetProvider,
 		launchTemplateProvider,
 	)
+	regionProvider := region.NewDefaultProvider(&region.Providers{
+		Region:                 *sess.Config.Region,
+		SubnetProvider:         subnetProvider,
+		SecurityGroupProvider:  securityGroupProvider,
+		AMIProvider:            amiProvider,
+		LaunchTemplateProvider: launchTemplateProvider,
+		PricingProvider:        pricingProvider,
+		InstanceTypeProvider:   instanceTypeProvider,
+		InstanceProvider:       instanceProvider,
+	}, func(name string) *region.Providers {
+		// The providers of an additional region share the cluster's caches of unavailable offerings and carbon intensity,
+		// which are keyed by zone, its pricing commitments, which are scoped by region, and its pricing overrides, which
+		// are scoped by zone
+		regionalSess := sess.Copy(&aws.Config{Region: aws.String(name)})
+		regionalEC2API := ec2.New(regionalSess)
+		regionalSubnetProvider := subnet.NewDefaultProvider(regionalEC2API, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval), cache.New(awscache.AvailableIPAddressTTL, awscache.DefaultCleanupInterval), cache.New(awscache.AssociatePublicIPAddressTTL, awscache.DefaultCleanupInterval))
+		regionalSecurityGroupProvider := securitygroup.NewDefaultProvider(regionalEC2API, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+		regionalPricingProvider := pricing.NewDefaultProvider(ctx, pricing.NewAPI(regionalSess, name), regionalEC2API, name, pricingOverrides)
+		regionalAMIProvider := amifamily.NewDefaultProvider(versionProvider, ssm.New(regionalSess), regionalEC2API, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+		regionalLaunchTemplateProvider := launchtemplate.NewDefaultProvider(
+			ctx,
+			cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval),
+			regionalEC2API,
+			eks.New(sess),
+			amifamily.NewResolver(regionalAMIProvider),
+			regionalSecurityGroupProvider,
+			regionalSubnetProvider,
+			caBundle,
+			operator.Elected(),
+			kubeDNSIP,
+			clusterEndpoint,
+		)
+		regionalInstanceTypeProvider := instancetype.NewDefaultProvider(
+			name,
+			cache.New(awscache.InstanceTypesAndZonesTTL, awscache.DefaultCleanupInterval),
+			regionalEC2API,
+			regionalSubnetProvider,
+			unavailableOfferingsCache,
+			regionalPricingProvider,
+			carbonProvider,
+			commitmentProvider,
+		)
+		return &region.Providers{
+			Region:                 name,
+			SubnetProvider:         regionalSubnetProvider,
+			SecurityGroupProvider:  regionalSecurityGroupProvider,
+			AMIProvider:            regionalAMIProvider,
+			LaunchTemplateProvider: regionalLaunchTemplateProvider,
+			PricingProvider:        regionalPricingProvider,
+			InstanceTypeProvider:   regionalInstanceTypeProvider,
+			InstanceProvider: instance.NewDefaultProvider(
+				ctx,
+				name,
+				regionalEC2API,
+				unavailableOfferingsCache,
+				regionalInstanceTypeProvider,
+				regionalSubnetProvider,
+				regionalLaunchTemplateProvider,
+			),
+		}
+	})
 
 	return ctx, &Operator{
 		Operator:                  operator,
@@ -190,8 +265,12 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		VersionProvider:           versionProvider,
 		LaunchTemplateProvider:    launchTemplateProvider,
 		PricingProvider:           pricingProvider,
+		PricingOverrides:          pricingOverrides,
+		CarbonProvider:            carbonProvider,
+		CommitmentProvider:        commitmentProvider,
 		InstanceTypesProvider:     instanceTypeProvider,
 		InstanceProvider:          instanceProvider,
+		RegionProvider:            regionProvider,
 	}
 }
 
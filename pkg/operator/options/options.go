@@ -20,6 +20,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
@@ -42,6 +43,14 @@ type Options struct {
 	VMMemoryOverheadPercent float64
 	InterruptionQueue       string
 	ReservedENIs            int
+
+	CarbonIntensityURL         string
+	CarbonIntensityAuthHeader  string
+	CarbonIntensityZoneMapping string
+	CarbonIntensityTTL         time.Duration
+
+	PricingCommitmentsFile    string
+	PricingOverridesConfigMap string
 }
 
 func (o *Options) AddFlags(fs *coreoptions.FlagSet) {
@@ -54,6 +63,12 @@ func (o *Options) AddFlags(fs *coreoptions.FlagSet) {
 	fs.Float64Var(&o.VMMemoryOverheadPercent, "vm-memory-overhead-percent", env.WithDefaultFloat64("VM_MEMORY_OVERHEAD_PERCENT", 0.075), "The VM memory overhead as a percent that will be subtracted from the total memory for all instance types.")
 	fs.StringVar(&o.InterruptionQueue, "interruption-queue", env.WithDefaultString("INTERRUPTION_QUEUE", ""), "Interruption queue is the name of the SQS queue used for processing interruption events from EC2. Interruption handling is disabled if not specified. Enabling interruption handling may require additional permissions on the controller service account. Additional permissions are outlined in the docs.")
 	fs.IntVar(&o.ReservedENIs, "reserved-enis", env.WithDefaultInt("RESERVED_ENIS", 0), "Reserved ENIs are not included in the calculations for max-pods or kube-reserved. This is most often used in the VPC CNI custom networking setup https://docs.aws.amazon.com/eks/latest/userguide/cni-custom-network.html.")
+	fs.StringVar(&o.CarbonIntensityURL, "carbon-intensity-url", env.WithDefaultString("CARBON_INTENSITY_URL", ""), "The HTTP endpoint polled for live grid carbon intensity. The grid zone is passed in the zone query parameter and the response follows the Electricity Maps carbon intensity format. The embedded per-region dataset is used if not specified.")
+	fs.StringVar(&o.CarbonIntensityAuthHeader, "carbon-intensity-auth-header", env.WithDefaultString("CARBON_INTENSITY_AUTH_HEADER", ""), "Header, in the form 'Name: value', sent with every request to the carbon intensity endpoint. For example 'auth-token: <token>' or 'Authorization: Bearer <token>'.")
+	fs.StringVar(&o.CarbonIntensityZoneMapping, "carbon-intensity-zone-mapping", env.WithDefaultString("CARBON_INTENSITY_ZONE_MAPPING", ""), "Comma separated mapping of AWS regions or zones to the grid zones of the carbon intensity endpoint, e.g. 'us-east-1=US-MIDA-PJM,eu-west-1=IE'. Required if carbon-intensity-url is set.")
+	fs.DurationVar(&o.CarbonIntensityTTL, "carbon-intensity-ttl", env.WithDefaultDuration("CARBON_INTENSITY_TTL", 2*time.Hour), "How long live carbon intensity is used after the last successful update before falling back to the embedded dataset.")
+	fs.StringVar(&o.PricingCommitmentsFile, "pricing-commitments-file", env.WithDefaultString("PRICING_COMMITMENTS_FILE", ""), "YAML file, such as a mounted ConfigMap, declaring the Savings Plans and Reserved Instances that cover on-demand instances at an effective price. Offerings are priced at the on-demand price if not specified.")
+	fs.StringVar(&o.PricingOverridesConfigMap, "pricing-overrides-configmap", env.WithDefaultString("PRICING_OVERRIDES_CONFIGMAP", ""), "Name of the ConfigMap, in the namespace Karpenter runs in, declaring multipliers or absolute prices that override the list price of instance types, such as for enterprise discounts or private pricing. Offerings are priced at the list price if not specified.")
 }
 
 func (o *Options) Parse(fs *coreoptions.FlagSet, args ...string) error {
@@ -69,6 +84,23 @@ func (o *Options) Parse(fs *coreoptions.FlagSet, args ...string) error {
 	return nil
 }
 
+// CarbonIntensityGridZones parses the carbon intensity zone mapping into a map of AWS region or zone to grid zone
+func (o *Options) CarbonIntensityGridZones() (map[string]string, error) {
+	gridZones := map[string]string{}
+	if o.CarbonIntensityZoneMapping == "" {
+		return gridZones, nil
+	}
+	for _, entry := range strings.Split(o.CarbonIntensityZoneMapping, ",") {
+		key, gridZone, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		key, gridZone = strings.TrimSpace(key), strings.TrimSpace(gridZone)
+		if !ok || key == "" || gridZone == "" {
+			return nil, fmt.Errorf("%q is not a valid zone mapping, expected <region or zone>=<grid zone>", entry)
+		}
+		gridZones[key] = gridZone
+	}
+	return gridZones, nil
+}
+
 func (o *Options) ToContext(ctx context.Context) context.Context {
 	return ToContext(ctx, o)
 }
@@ -17,6 +17,7 @@ package options
 import (
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"go.uber.org/multierr"
@@ -29,6 +30,7 @@ func (o Options) Validate() error {
 		o.validateAssumeRoleDuration(),
 		o.validateReservedENIs(),
 		o.validateRequiredFields(),
+		o.validateCarbonIntensity(),
 	)
 }
 
@@ -72,3 +74,29 @@ func (o Options) validateRequiredFields() error {
 	}
 	return nil
 }
+
+func (o Options) validateCarbonIntensity() error {
+	if o.CarbonIntensityURL == "" {
+		return nil
+	}
+	endpoint, err := url.Parse(o.CarbonIntensityURL)
+	if err != nil || !endpoint.IsAbs() || endpoint.Hostname() == "" {
+		return fmt.Errorf("%q is not a valid carbon-intensity-url URL", o.CarbonIntensityURL)
+	}
+	if o.CarbonIntensityAuthHeader != "" {
+		if name, _, ok := strings.Cut(o.CarbonIntensityAuthHeader, ":"); !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("carbon-intensity-auth-header must be in the form 'Name: value'")
+		}
+	}
+	gridZones, err := o.CarbonIntensityGridZones()
+	if err != nil {
+		return fmt.Errorf("validating carbon-intensity-zone-mapping, %w", err)
+	}
+	if len(gridZones) == 0 {
+		return fmt.Errorf("missing field, carbon-intensity-zone-mapping is required when carbon-intensity-url is set")
+	}
+	if o.CarbonIntensityTTL <= 0 {
+		return fmt.Errorf("carbon-intensity-ttl must be positive")
+	}
+	return nil
+}
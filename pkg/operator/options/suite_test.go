@@ -65,7 +65,13 @@ var _ = Describe("Options", func() {
 			"--isolated-vpc",
 			"--vm-memory-overhead-percent", "0.1",
 			"--interruption-queue", "env-cluster",
-			"--reserved-enis", "10")
+			"--reserved-enis", "10",
+			"--carbon-intensity-url", "https://env-carbon",
+			"--carbon-intensity-auth-header", "auth-token: env-token",
+			"--carbon-intensity-zone-mapping", "us-west-2=US-NW-BPAT",
+			"--carbon-intensity-ttl", "30m",
+			"--pricing-commitments-file", "/etc/karpenter/commitments.yaml",
+			"--pricing-overrides-configmap", "env-pricing-overrides")
 		Expect(err).ToNot(HaveOccurred())
 		expectOptionsEqual(opts, test.Options(test.OptionsFields{
 			AssumeRoleARN:           lo.ToPtr("env-role"),
@@ -77,6 +83,14 @@ var _ = Describe("Options", func() {
 			VMMemoryOverheadPercent: lo.ToPtr[float64](0.1),
 			InterruptionQueue:       lo.ToPtr("env-cluster"),
 			ReservedENIs:            lo.ToPtr(10),
+
+			CarbonIntensityURL:         lo.ToPtr("https://env-carbon"),
+			CarbonIntensityAuthHeader:  lo.ToPtr("auth-token: env-token"),
+			CarbonIntensityZoneMapping: lo.ToPtr("us-west-2=US-NW-BPAT"),
+			CarbonIntensityTTL:         lo.ToPtr(30 * time.Minute),
+
+			PricingCommitmentsFile:    lo.ToPtr("/etc/karpenter/commitments.yaml"),
+			PricingOverridesConfigMap: lo.ToPtr("env-pricing-overrides"),
 		}))
 	})
 	It("should correctly fallback to env vars when CLI flags aren't set", func() {
@@ -89,6 +103,12 @@ var _ = Describe("Options", func() {
 		os.Setenv("VM_MEMORY_OVERHEAD_PERCENT", "0.1")
 		os.Setenv("INTERRUPTION_QUEUE", "env-cluster")
 		os.Setenv("RESERVED_ENIS", "10")
+		os.Setenv("CARBON_INTENSITY_URL", "https://env-carbon")
+		os.Setenv("CARBON_INTENSITY_AUTH_HEADER", "auth-token: env-token")
+		os.Setenv("CARBON_INTENSITY_ZONE_MAPPING", "us-west-2=US-NW-BPAT")
+		os.Setenv("CARBON_INTENSITY_TTL", "30m")
+		os.Setenv("PRICING_COMMITMENTS_FILE", "/etc/karpenter/commitments.yaml")
+		os.Setenv("PRICING_OVERRIDES_CONFIGMAP", "env-pricing-overrides")
 
 		// Add flags after we set the environment variables so that the parsing logic correctly refers
 		// to the new environment variable values
@@ -105,6 +125,14 @@ var _ = Describe("Options", func() {
 			VMMemoryOverheadPercent: lo.ToPtr[float64](0.1),
 			InterruptionQueue:       lo.ToPtr("env-cluster"),
 			ReservedENIs:            lo.ToPtr(10),
+
+			CarbonIntensityURL:         lo.ToPtr("https://env-carbon"),
+			CarbonIntensityAuthHeader:  lo.ToPtr("auth-token: env-token"),
+			CarbonIntensityZoneMapping: lo.ToPtr("us-west-2=US-NW-BPAT"),
+			CarbonIntensityTTL:         lo.ToPtr(30 * time.Minute),
+
+			PricingCommitmentsFile:    lo.ToPtr("/etc/karpenter/commitments.yaml"),
+			PricingOverridesConfigMap: lo.ToPtr("env-pricing-overrides"),
 		}))
 	})
 
@@ -132,6 +160,38 @@ var _ = Describe("Options", func() {
 			err := opts.Parse(fs, "--cluster-name", "test-cluster", "--reserved-enis", "-1")
 			Expect(err).To(HaveOccurred())
 		})
+		It("should fail when carbonIntensityURL is invalid (not absolute)", func() {
+			err := opts.Parse(fs, "--cluster-name", "test-cluster", "--carbon-intensity-url", "api.electricitymap.org/v3/carbon-intensity/latest",
+				"--carbon-intensity-zone-mapping", "us-west-2=US-NW-BPAT")
+			Expect(err).To(HaveOccurred())
+		})
+		It("should fail when carbonIntensityURL is set without a zone mapping", func() {
+			err := opts.Parse(fs, "--cluster-name", "test-cluster", "--carbon-intensity-url", "https://api.electricitymap.org/v3/carbon-intensity/latest")
+			Expect(err).To(HaveOccurred())
+		})
+		It("should fail when carbonIntensityZoneMapping is malformed", func() {
+			err := opts.Parse(fs, "--cluster-name", "test-cluster", "--carbon-intensity-url", "https://api.electricitymap.org/v3/carbon-intensity/latest",
+				"--carbon-intensity-zone-mapping", "us-west-2:US-NW-BPAT")
+			Expect(err).To(HaveOccurred())
+		})
+		It("should fail when carbonIntensityAuthHeader has no header name", func() {
+			err := opts.Parse(fs, "--cluster-name", "test-cluster", "--carbon-intensity-url", "https://api.electricitymap.org/v3/carbon-intensity/latest",
+				"--carbon-intensity-zone-mapping", "us-west-2=US-NW-BPAT", "--carbon-intensity-auth-header", "token")
+			Expect(err).To(HaveOccurred())
+		})
+		It("should fail when carbonIntensityTTL is not positive", func() {
+			err := opts.Parse(fs, "--cluster-name", "test-cluster", "--carbon-intensity-url", "https://api.electricitymap.org/v3/carbon-intensity/latest",
+				"--carbon-intensity-zone-mapping", "us-west-2=US-NW-BPAT", "--carbon-intensity-ttl", "0s")
+			Expect(err).To(HaveOccurred())
+		})
+		It("should parse the carbon intensity zone mapping", func() {
+			err := opts.Parse(fs, "--cluster-name", "test-cluster", "--carbon-intensity-url", "https://api.electricitymap.org/v3/carbon-intensity/latest",
+				"--carbon-intensity-zone-mapping", "us-west-2=US-NW-BPAT, us-east-1a = US-MIDA-PJM")
+			Expect(err).ToNot(HaveOccurred())
+			gridZones, err := opts.CarbonIntensityGridZones()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gridZones).To(Equal(map[string]string{"us-west-2": "US-NW-BPAT", "us-east-1a": "US-MIDA-PJM"}))
+		})
 	})
 })
 
@@ -146,4 +206,10 @@ func expectOptionsEqual(optsA *options.Options, optsB *options.Options) {
 	Expect(optsA.VMMemoryOverheadPercent).To(Equal(optsB.VMMemoryOverheadPercent))
 	Expect(optsA.InterruptionQueue).To(Equal(optsB.InterruptionQueue))
 	Expect(optsA.ReservedENIs).To(Equal(optsB.ReservedENIs))
+	Expect(optsA.CarbonIntensityURL).To(Equal(optsB.CarbonIntensityURL))
+	Expect(optsA.CarbonIntensityAuthHeader).To(Equal(optsB.CarbonIntensityAuthHeader))
+	Expect(optsA.CarbonIntensityZoneMapping).To(Equal(optsB.CarbonIntensityZoneMapping))
+	Expect(optsA.CarbonIntensityTTL).To(Equal(optsB.CarbonIntensityTTL))
+	Expect(optsA.PricingCommitmentsFile).To(Equal(optsB.PricingCommitmentsFile))
+	Expect(optsA.PricingOverridesConfigMap).To(Equal(optsB.PricingOverridesConfigMap))
 }
@@ -0,0 +1,94 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go"
+
+	"github.com/aws/karpenter-provider-aws/pkg/utils"
+)
+
+// IntensityResponse is the body returned by a live carbon intensity endpoint. It follows the shape of the Electricity
+// Maps carbon intensity API so that it can be used directly, while other sources such as WattTime can be fronted by a
+// small adapter that serves the same shape.
+type IntensityResponse struct {
+	Zone            string     `json:"zone"`
+	CarbonIntensity float64    `json:"carbonIntensity"`
+	Datetime        time.Time  `json:"datetime"`
+	Forecast        []Forecast `json:"forecast,omitempty"`
+}
+
+// Forecast is the forecasted grid intensity, in gCO2e/kWh, at a point in time
+type Forecast struct {
+	CarbonIntensity float64   `json:"carbonIntensity"`
+	Datetime        time.Time `json:"datetime"`
+}
+
+// getIntensity requests the current and forecasted intensity of a grid zone, retrying on transient failures
+func (p *DefaultProvider) getIntensity(ctx context.Context, endpoint, authHeader, gridZone string) (*IntensityResponse, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parsing carbon intensity url, %w", err)
+	}
+	query := u.Query()
+	query.Set("zone", gridZone)
+	u.RawQuery = query.Encode()
+
+	out := &IntensityResponse{}
+	if err = retry.Do(func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+		if name, value, ok := strings.Cut(authHeader, ":"); ok {
+			req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+			// client errors, other than throttling, won't succeed on a retry
+			if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+				return retry.Unrecoverable(err)
+			}
+			return err
+		}
+		if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return retry.Unrecoverable(fmt.Errorf("decoding response, %w", err))
+		}
+		return nil
+	}, retry.Context(ctx), retry.Attempts(3), retry.LastErrorOnly(true)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// isRegion returns true if the key of a zone mapping is a region rather than a zone. Every zone name, including those of
+// local and Wavelength zones, is prefixed by its region's name, so only a region's name is its own region.
+func isRegion(key string) bool {
+	region, ok := utils.RegionForZone(key)
+	return ok && region == key
+}
@@ -0,0 +1,122 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+)
+
+// BudgetPeriod is the calendar period over which a carbon budget is consumed before it resets
+type BudgetPeriod string
+
+const (
+	BudgetPeriodMonthly   BudgetPeriod = "Monthly"
+	BudgetPeriodQuarterly BudgetPeriod = "Quarterly"
+)
+
+// BudgetAction is what happens to launches for a NodePool once its carbon budget is exhausted
+type BudgetAction string
+
+const (
+	// BudgetActionStrict escalates the carbon mode of the NodePool's EC2NodeClass to Strict, so that only offerings
+	// below its maximum intensity are launched
+	BudgetActionStrict BudgetAction = "Strict"
+	// BudgetActionRefuseNonCritical refuses launches unless the NodeClaim requires the carbon-critical label
+	BudgetActionRefuseNonCritical BudgetAction = "RefuseNonCritical"
+)
+
+// Budget is the carbon budget of a NodePool, along with how much of it has been consumed in the current period
+type Budget struct {
+	KgCO2e float64
+	Period BudgetPeriod
+	Action BudgetAction
+
+	// PeriodStart is the start of the period that ConsumedKgCO2e was accounted in
+	PeriodStart    time.Time
+	ConsumedKgCO2e float64
+}
+
+// NodePoolBudget parses the carbon budget annotations of a NodePool. It returns nil if the NodePool has no budget.
+func NodePoolBudget(nodePool *corev1beta1.NodePool) (*Budget, error) {
+	value, ok := nodePool.Annotations[v1beta1.AnnotationCarbonBudget]
+	if !ok {
+		return nil, nil
+	}
+	kg, err := strconv.ParseFloat(value, 64)
+	if err != nil || kg < 0 || math.IsInf(kg, 0) || math.IsNaN(kg) {
+		return nil, fmt.Errorf("invalid %s %q, must be a non-negative number", v1beta1.AnnotationCarbonBudget, value)
+	}
+	budget := &Budget{KgCO2e: kg, Period: BudgetPeriodQuarterly, Action: BudgetActionStrict}
+	if value, ok := nodePool.Annotations[v1beta1.AnnotationCarbonBudgetPeriod]; ok {
+		switch BudgetPeriod(value) {
+		case BudgetPeriodMonthly, BudgetPeriodQuarterly:
+			budget.Period = BudgetPeriod(value)
+		default:
+			return nil, fmt.Errorf("invalid %s %q, must be one of %s or %s", v1beta1.AnnotationCarbonBudgetPeriod, value, BudgetPeriodMonthly, BudgetPeriodQuarterly)
+		}
+	}
+	if value, ok := nodePool.Annotations[v1beta1.AnnotationCarbonBudgetAction]; ok {
+		switch BudgetAction(value) {
+		case BudgetActionStrict, BudgetActionRefuseNonCritical:
+			budget.Action = BudgetAction(value)
+		default:
+			return nil, fmt.Errorf("invalid %s %q, must be one of %s or %s", v1beta1.AnnotationCarbonBudgetAction, value, BudgetActionStrict, BudgetActionRefuseNonCritical)
+		}
+	}
+	// The status annotations are written by Karpenter, so anything unparseable is treated as nothing consumed yet
+	if t, err := time.Parse(time.RFC3339, nodePool.Annotations[v1beta1.AnnotationCarbonBudgetPeriodStart]); err == nil {
+		budget.PeriodStart = t
+	}
+	if consumed, err := strconv.ParseFloat(nodePool.Annotations[v1beta1.AnnotationCarbonBudgetConsumed], 64); err == nil {
+		budget.ConsumedKgCO2e = consumed
+	}
+	return budget, nil
+}
+
+// CurrentPeriodStart returns the start, in UTC, of the budget period containing now
+func (b *Budget) CurrentPeriodStart(now time.Time) time.Time {
+	now = now.UTC()
+	month := now.Month()
+	if b.Period == BudgetPeriodQuarterly {
+		month -= (month - 1) % 3
+	}
+	return time.Date(now.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Consumed returns the emissions consumed in the budget period containing now, which is nothing if the last accounted
+// period has since ended
+func (b *Budget) Consumed(now time.Time) float64 {
+	if !b.PeriodStart.Equal(b.CurrentPeriodStart(now)) {
+		return 0
+	}
+	return b.ConsumedKgCO2e
+}
+
+// Remaining returns the emissions left in the budget for the period containing now
+func (b *Budget) Remaining(now time.Time) float64 {
+	return math.Max(b.KgCO2e-b.Consumed(now), 0)
+}
+
+// Exhausted returns true if the budget for the period containing now has been consumed
+func (b *Budget) Exhausted(now time.Time) bool {
+	return b.Consumed(now) >= b.KgCO2e
+}
@@ -0,0 +1,358 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
+	"go.uber.org/multierr"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/karpenter/pkg/utils/pretty"
+
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/utils"
+)
+
+// DefaultPUE is the power usage effectiveness assumed for regions without a published value
+const DefaultPUE = 1.135
+
+// MinDeferralReduction is the fraction by which the forecast intensity must fall below the current intensity for a
+// launch to be deferred, so that we don't hold back capacity for marginal gains
+const MinDeferralReduction = 0.25
+
+// Carbon tiers bucket grid intensity into a small set of label values that pods can select on
+const (
+	TierVeryLow  = "very-low"
+	TierLow      = "low"
+	TierMedium   = "medium"
+	TierHigh     = "high"
+	TierVeryHigh = "very-high"
+)
+
+// DataSource is where the grid intensity of a region or zone comes from
+type DataSource string
+
+const (
+	// DataSourceLive is intensity retrieved from the carbon intensity endpoint within the TTL
+	DataSourceLive DataSource = "Live"
+	// DataSourceStatic is intensity from the embedded per-region dataset
+	DataSourceStatic DataSource = "Static"
+)
+
+// Intensity is the grid intensity, in gCO2e/kWh, of a region or, when Zone is set, of a zone within the region
+type Intensity struct {
+	Region      string
+	Zone        string
+	GramsPerKWh float64
+}
+
+type Provider interface {
+	LivenessProbe(*http.Request) error
+	RegionIntensity(string) (float64, bool)
+	ZoneIntensity(string) (float64, bool)
+	ZoneDataSource(string) (DataSource, time.Time)
+	Intensities() []Intensity
+	Forecast(string) []Forecast
+	GreenWindow([]string, time.Time, time.Time) (float64, Forecast, bool)
+	EmbodiedEmissions(*ec2.InstanceTypeInfo, *ec2.InstanceTypeInfo) Embodied
+	SeqNum() uint64
+	Update(context.Context) error
+}
+
+// DefaultProvider provides grid carbon intensity data, in grams of CO2 equivalent per kWh, to the AWS cloud provider so
+// that other subsystems can account for the emissions of the capacity they launch. This is initialized at startup with a
+// static per-region average intensity to support air-gapped clusters where no live intensity data is available. Intensity
+// is tracked per region and, where a source offers finer granularity, per zone. Zones without their own data inherit the
+// intensity of the region the provider runs in. When a carbon intensity endpoint is configured, the regions and zones in
+// the zone mapping are refreshed from it on every update. In the event that an update fails, the previous intensity data
+// is retained and used until it is older than the configured TTL, at which point it falls back to the static data.
+type DefaultProvider struct {
+	region     string
+	clk        clock.Clock
+	httpClient *http.Client
+	cm         *pretty.ChangeMonitor
+
+	muIntensity     sync.RWMutex
+	regionIntensity map[string]float64
+	zoneIntensity   map[string]float64
+	// forecast and lastUpdated are keyed by the region or zone of the zone mapping that live data was retrieved for
+	forecast    map[string][]Forecast
+	lastUpdated map[string]time.Time
+	// seqNum is a monotonically increasing change counter so that consumers can cheaply detect changes in intensity
+	seqNum uint64
+}
+
+func NewDefaultProvider(_ context.Context, clk clock.Clock, region string) *DefaultProvider {
+	p := &DefaultProvider{
+		region:     region,
+		clk:        clk,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cm:         pretty.NewChangeMonitor(),
+	}
+	// sets the intensity data from the static default state for the provider
+	p.Reset()
+
+	return p
+}
+
+// RegionIntensity returns the last known grid intensity for a given region
+func (p *DefaultProvider) RegionIntensity(region string) (float64, bool) {
+	p.muIntensity.RLock()
+	defer p.muIntensity.RUnlock()
+	intensity, ok := p.regionIntensity[region]
+	return intensity, ok
+}
+
+// ZoneIntensity returns the last known grid intensity for a given zone, falling back to the intensity of the zone's
+// region if there is no zone specific data
+func (p *DefaultProvider) ZoneIntensity(zone string) (float64, bool) {
+	p.muIntensity.RLock()
+	defer p.muIntensity.RUnlock()
+	if intensity, ok := p.zoneIntensity[zone]; ok {
+		return intensity, true
+	}
+	intensity, ok := p.regionIntensity[p.regionForZone(zone)]
+	return intensity, ok
+}
+
+// ZoneDataSource returns where the intensity of a zone comes from and, for live data, when it was last updated. Zones
+// without their own live data use the live data of their region, if there is any.
+func (p *DefaultProvider) ZoneDataSource(zone string) (DataSource, time.Time) {
+	p.muIntensity.RLock()
+	defer p.muIntensity.RUnlock()
+	if lastUpdated, ok := p.lastUpdated[zone]; ok {
+		return DataSourceLive, lastUpdated
+	}
+	if lastUpdated, ok := p.lastUpdated[p.regionForZone(zone)]; ok {
+		return DataSourceLive, lastUpdated
+	}
+	return DataSourceStatic, time.Time{}
+}
+
+// Intensities returns all of the grid intensity data the provider has, with zone specific data attributed to the
+// zone's region
+func (p *DefaultProvider) Intensities() []Intensity {
+	p.muIntensity.RLock()
+	defer p.muIntensity.RUnlock()
+	intensities := make([]Intensity, 0, len(p.regionIntensity)+len(p.zoneIntensity))
+	for region, intensity := range p.regionIntensity {
+		intensities = append(intensities, Intensity{Region: region, GramsPerKWh: intensity})
+	}
+	for zone, intensity := range p.zoneIntensity {
+		intensities = append(intensities, Intensity{Region: p.regionForZone(zone), Zone: zone, GramsPerKWh: intensity})
+	}
+	return intensities
+}
+
+// regionForZone returns the region of a zone, which is assumed to be the provider's region for zones that aren't
+// named after a region
+func (p *DefaultProvider) regionForZone(zone string) string {
+	if region, ok := utils.RegionForZone(zone); ok {
+		return region
+	}
+	return p.region
+}
+
+// Forecast returns the last known intensity forecast for a given zone, falling back to the forecast for the zone's
+// region if there is no zone specific forecast
+func (p *DefaultProvider) Forecast(zone string) []Forecast {
+	p.muIntensity.RLock()
+	defer p.muIntensity.RUnlock()
+	if forecast, ok := p.forecast[zone]; ok {
+		return forecast
+	}
+	return p.forecast[p.regionForZone(zone)]
+}
+
+// GreenWindow returns the earliest forecast point after now and before the deadline at which the intensity of any of the
+// zones is at least MinDeferralReduction lower than the lowest current intensity of the zones. The lowest current
+// intensity is returned alongside it.
+func (p *DefaultProvider) GreenWindow(zones []string, now, deadline time.Time) (float64, Forecast, bool) {
+	return FindGreenWindow(p, zones, now, deadline)
+}
+
+// FindGreenWindow finds the green window of a set of zones from the current intensity and forecast of a provider
+func FindGreenWindow(p Provider, zones []string, now, deadline time.Time) (float64, Forecast, bool) {
+	current := math.MaxFloat64
+	for _, zone := range zones {
+		if intensity, ok := p.ZoneIntensity(zone); ok {
+			current = math.Min(current, intensity)
+		}
+	}
+	if current == math.MaxFloat64 {
+		return 0, Forecast{}, false
+	}
+	var window Forecast
+	found := false
+	for _, zone := range zones {
+		for _, f := range p.Forecast(zone) {
+			if !f.Datetime.After(now) || f.Datetime.After(deadline) || f.CarbonIntensity > current*(1-MinDeferralReduction) {
+				continue
+			}
+			if !found || f.Datetime.Before(window.Datetime) {
+				window, found = f, true
+			}
+		}
+	}
+	return current, window, found
+}
+
+// Update refreshes the grid intensity data from the carbon intensity endpoint, if one is configured. Without an
+// endpoint, this only reports the static data that the provider is currently serving.
+func (p *DefaultProvider) Update(ctx context.Context) error {
+	var err error
+	if options.FromContext(ctx).CarbonIntensityURL != "" {
+		err = p.updateLiveIntensity(ctx)
+	}
+	p.muIntensity.RLock()
+	defer p.muIntensity.RUnlock()
+	regionChanged := p.cm.HasChanged("region-intensity", p.regionIntensity)
+	zoneChanged := p.cm.HasChanged("zone-intensity", p.zoneIntensity)
+	if regionChanged || zoneChanged {
+		atomic.AddUint64(&p.seqNum, 1)
+		log.FromContext(ctx).WithValues("regions", len(p.regionIntensity), "zones", len(p.zoneIntensity)).V(1).Info("updated carbon intensity")
+	}
+	return err
+}
+
+func (p *DefaultProvider) updateLiveIntensity(ctx context.Context) error {
+	opts := options.FromContext(ctx)
+	gridZones, err := opts.CarbonIntensityGridZones()
+	if err != nil {
+		return fmt.Errorf("parsing carbon intensity zone mapping, %w", err)
+	}
+	// several regions or zones may map onto the same grid zone, so we only request each grid zone once
+	responses := map[string]*IntensityResponse{}
+	var errs error
+	for key, gridZone := range gridZones {
+		if _, ok := responses[gridZone]; !ok {
+			out, err := p.getIntensity(ctx, opts.CarbonIntensityURL, opts.CarbonIntensityAuthHeader, gridZone)
+			if err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("getting carbon intensity for grid zone %s, %w", gridZone, err))
+			}
+			responses[gridZone] = out
+		}
+		if out := responses[gridZone]; out != nil {
+			p.setLiveIntensity(key, out)
+		}
+	}
+	p.expireLiveIntensity(ctx, opts.CarbonIntensityTTL)
+	return errs
+}
+
+func (p *DefaultProvider) setLiveIntensity(key string, out *IntensityResponse) {
+	p.muIntensity.Lock()
+	defer p.muIntensity.Unlock()
+	if isRegion(key) {
+		p.regionIntensity[key] = out.CarbonIntensity
+	} else {
+		p.zoneIntensity[key] = out.CarbonIntensity
+	}
+	p.forecast[key] = out.Forecast
+	p.lastUpdated[key] = p.clk.Now()
+}
+
+// expireLiveIntensity falls back to the static data for any region or zone that hasn't been successfully updated
+// within the TTL, so that we don't keep making decisions on intensity that has long since changed
+func (p *DefaultProvider) expireLiveIntensity(ctx context.Context, ttl time.Duration) {
+	p.muIntensity.Lock()
+	defer p.muIntensity.Unlock()
+	for key, lastUpdated := range p.lastUpdated {
+		if p.clk.Since(lastUpdated) <= ttl {
+			continue
+		}
+		if isRegion(key) {
+			if intensity, ok := p.initialRegionIntensity(key); ok {
+				p.regionIntensity[key] = intensity
+			} else {
+				delete(p.regionIntensity, key)
+			}
+		} else {
+			delete(p.zoneIntensity, key)
+		}
+		delete(p.forecast, key)
+		delete(p.lastUpdated, key)
+		log.FromContext(ctx).WithValues("key", key, "last-updated", lastUpdated).Info("carbon intensity is stale, falling back to static data")
+	}
+}
+
+// SeqNum returns a counter that is incremented whenever the intensity data changes
+func (p *DefaultProvider) SeqNum() uint64 {
+	return atomic.LoadUint64(&p.seqNum)
+}
+
+func (p *DefaultProvider) LivenessProbe(_ *http.Request) error {
+	// ensure we don't deadlock and nolint for the empty critical section
+	p.muIntensity.Lock()
+	//nolint: staticcheck
+	p.muIntensity.Unlock()
+	return nil
+}
+
+// PUE returns the power usage effectiveness of the data centers in a region
+func PUE(region string) float64 {
+	if pue, ok := RegionPUE[region]; ok {
+		return pue
+	}
+	return DefaultPUE
+}
+
+// Tier returns the carbon tier of a grid intensity, in gCO2e/kWh
+func Tier(intensity float64) string {
+	switch {
+	case intensity < 100:
+		return TierVeryLow
+	case intensity < 250:
+		return TierLow
+	case intensity < 400:
+		return TierMedium
+	case intensity < 600:
+		return TierHigh
+	default:
+		return TierVeryHigh
+	}
+}
+
+func (p *DefaultProvider) Reset() {
+	p.muIntensity.Lock()
+	defer p.muIntensity.Unlock()
+	p.regionIntensity = lo.Assign(InitialRegionIntensity)
+	p.regionIntensity[p.region], _ = p.initialRegionIntensity(p.region)
+	p.zoneIntensity = map[string]float64{}
+	p.forecast = map[string][]Forecast{}
+	p.lastUpdated = map[string]time.Time{}
+	atomic.AddUint64(&p.seqNum, 1)
+}
+
+func (p *DefaultProvider) initialRegionIntensity(region string) (float64, bool) {
+	if intensity, ok := InitialRegionIntensity[region]; ok {
+		return intensity, true
+	}
+	// if we don't have region specific data, fall back to the always available us-east-1 so that we still have a
+	// relative ordering for the offerings in our region
+	if region == p.region {
+		return InitialRegionIntensity["us-east-1"], true
+	}
+	return 0, false
+}
@@ -0,0 +1,90 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon
+
+import (
+	"math"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
+)
+
+// Manufacturing emissions of a host, following the Cloud Carbon Footprint methodology. A base server has two CPUs,
+// 16 GB of memory and no GPUs or local drives; everything a host has beyond that adds to its embodied emissions.
+const (
+	baseHostKgCO2e    = 1000.0
+	baseHostCPUs      = 2
+	baseHostMemoryGB  = 16.0
+	memoryKgCO2ePerGB = 533.0 / 384.0
+	cpuKgCO2e         = 100.0
+	gpuKgCO2e         = 150.0
+	ssdKgCO2e         = 100.0
+	hddKgCO2e         = 50.0
+	// vcpusPerCPU is used to estimate the number of CPU sockets in a host, which DescribeInstanceTypes doesn't report
+	vcpusPerCPU = 64
+	// HostLifespanHours is the period over which the embodied emissions of a host are amortized
+	HostLifespanHours = 4 * 365 * 24
+)
+
+// Embodied is the share of a host's manufacturing emissions attributed to an instance type
+type Embodied struct {
+	// KgCO2e is the instance type's share of the total manufacturing emissions of its host
+	KgCO2e float64
+	// GramsPerHour is KgCO2e amortized over the lifespan of the host
+	GramsPerHour float64
+}
+
+// EmbodiedEmissions estimates the embodied emissions of an instance type running on a given host, which is usually the
+// largest instance type in the same family. The emissions of the host are scaled by the fraction of the host's vCPUs
+// that the instance type uses.
+func (p *DefaultProvider) EmbodiedEmissions(info *ec2.InstanceTypeInfo, host *ec2.InstanceTypeInfo) Embodied {
+	if host == nil || vcpus(host) < vcpus(info) {
+		host = info
+	}
+	if vcpus(host) == 0 {
+		return Embodied{}
+	}
+	kg := hostKgCO2e(host) * float64(vcpus(info)) / float64(vcpus(host))
+	return Embodied{
+		KgCO2e:       kg,
+		GramsPerHour: kg * 1000 / HostLifespanHours,
+	}
+}
+
+func hostKgCO2e(host *ec2.InstanceTypeInfo) float64 {
+	kg := baseHostKgCO2e
+	cpus := math.Ceil(float64(vcpus(host)) / vcpusPerCPU)
+	kg += math.Max(0, cpus-baseHostCPUs) * cpuKgCO2e
+	memoryGB := float64(lo.FromPtr(host.MemoryInfo.SizeInMiB)) / 1024
+	kg += math.Max(0, memoryGB-baseHostMemoryGB) * memoryKgCO2ePerGB
+	if host.GpuInfo != nil {
+		for _, gpu := range host.GpuInfo.Gpus {
+			kg += float64(lo.FromPtr(gpu.Count)) * gpuKgCO2e
+		}
+	}
+	if host.InstanceStorageInfo != nil {
+		for _, disk := range host.InstanceStorageInfo.Disks {
+			kg += float64(lo.FromPtr(disk.Count)) * lo.Ternary(lo.FromPtr(disk.Type) == ec2.DiskTypeHdd, hddKgCO2e, ssdKgCO2e)
+		}
+	}
+	return kg
+}
+
+func vcpus(info *ec2.InstanceTypeInfo) int64 {
+	if info.VCpuInfo == nil {
+		return 0
+	}
+	return lo.FromPtr(info.VCpuInfo.DefaultVCpus)
+}
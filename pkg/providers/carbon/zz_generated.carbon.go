@@ -0,0 +1,81 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package carbon
+
+// GENERATED FILE. DO NOT EDIT DIRECTLY.
+// Update hack/code/carbon_gen/grid_intensity.csv and re-generate to edit
+
+var (
+	// InitialRegionIntensity is the average grid intensity of each region in gCO2e/kWh
+	InitialRegionIntensity = map[string]float64{
+		"af-south-1":     900.600000,
+		"ap-east-1":      710.000000,
+		"ap-northeast-1": 465.800000,
+		"ap-northeast-2": 415.600000,
+		"ap-northeast-3": 465.800000,
+		"ap-south-1":     708.200000,
+		"ap-southeast-1": 408.000000,
+		"ap-southeast-2": 760.000000,
+		"ap-southeast-3": 717.700000,
+		"ca-central-1":   120.000000,
+		"cn-north-1":     537.400000,
+		"cn-northwest-1": 537.400000,
+		"eu-central-1":   311.000000,
+		"eu-north-1":     8.800000,
+		"eu-south-1":     223.300000,
+		"eu-west-1":      278.600000,
+		"eu-west-2":      225.000000,
+		"eu-west-3":      51.100000,
+		"me-central-1":   404.100000,
+		"me-south-1":     505.900000,
+		"sa-east-1":      61.700000,
+		"us-east-1":      379.069000,
+		"us-east-2":      410.608000,
+		"us-gov-east-1":  379.069000,
+		"us-gov-west-1":  322.167000,
+		"us-west-1":      322.167000,
+		"us-west-2":      322.167000,
+	}
+	// RegionPUE is the power usage effectiveness of the data centers in each region
+	RegionPUE = map[string]float64{
+		"af-south-1":     1.135000,
+		"ap-east-1":      1.135000,
+		"ap-northeast-1": 1.135000,
+		"ap-northeast-2": 1.135000,
+		"ap-northeast-3": 1.135000,
+		"ap-south-1":     1.135000,
+		"ap-southeast-1": 1.135000,
+		"ap-southeast-2": 1.135000,
+		"ap-southeast-3": 1.135000,
+		"ca-central-1":   1.135000,
+		"cn-north-1":     1.135000,
+		"cn-northwest-1": 1.135000,
+		"eu-central-1":   1.135000,
+		"eu-north-1":     1.135000,
+		"eu-south-1":     1.135000,
+		"eu-west-1":      1.135000,
+		"eu-west-2":      1.135000,
+		"eu-west-3":      1.135000,
+		"me-central-1":   1.135000,
+		"me-south-1":     1.135000,
+		"sa-east-1":      1.135000,
+		"us-east-1":      1.135000,
+		"us-east-2":      1.135000,
+		"us-gov-east-1":  1.135000,
+		"us-gov-west-1":  1.135000,
+		"us-west-1":      1.135000,
+		"us-west-2":      1.135000,
+	}
+)
@@ -0,0 +1,253 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/utils/pretty"
+
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+)
+
+// Commitment is a Savings Plan or Reserved Instance that covers a number of on-demand instances of an instance family
+// at an effective price below the on-demand price
+type Commitment struct {
+	Name string `json:"name"`
+	// InstanceFamily is the instance family that the commitment covers, such as m5
+	InstanceFamily string `json:"instanceFamily"`
+	// Sizes are the instance sizes that the commitment covers, such as large. All sizes are covered if this is empty.
+	Sizes []string `json:"sizes,omitempty"`
+	// OperatingSystem is the operating system of the instances that the commitment covers, linux or windows. Windows
+	// instances are billed for their license, so commitments only cover linux instances unless this is windows.
+	OperatingSystem string `json:"operatingSystem,omitempty"`
+	// Region and Zone scope the commitment to the instances in a region or zone, such as a regional or zonal Reserved
+	// Instance. Commitments without either, such as Compute Savings Plans, cover instances in every region.
+	Region string `json:"region,omitempty"`
+	Zone   string `json:"zone,omitempty"`
+	// Count is the number of instances that the commitment covers
+	Count int `json:"count"`
+	// EffectivePrice is the hourly price, in US dollars, that each covered instance effectively costs
+	EffectivePrice *float64 `json:"effectivePrice,omitempty"`
+	// EffectiveRate is the fraction of the on-demand price that each covered instance effectively costs, such as 0.6 for
+	// a 40% discount
+	EffectiveRate *float64 `json:"effectiveRate,omitempty"`
+}
+
+// Commitments is the format of the pricing commitments file
+type Commitments struct {
+	Commitments []Commitment `json:"commitments"`
+}
+
+func (c Commitment) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if c.InstanceFamily == "" {
+		return fmt.Errorf("instanceFamily is required")
+	}
+	if c.OperatingSystem != "" && c.OperatingSystem != string(v1.Linux) && c.OperatingSystem != string(v1.Windows) {
+		return fmt.Errorf("operatingSystem must be one of %s or %s", v1.Linux, v1.Windows)
+	}
+	if c.Count <= 0 {
+		return fmt.Errorf("count must be positive")
+	}
+	if (c.EffectivePrice == nil) == (c.EffectiveRate == nil) {
+		return fmt.Errorf("exactly one of effectivePrice and effectiveRate is required")
+	}
+	if c.EffectivePrice != nil && *c.EffectivePrice < 0 {
+		return fmt.Errorf("effectivePrice must not be negative")
+	}
+	if c.EffectiveRate != nil && (*c.EffectiveRate < 0 || *c.EffectiveRate > 1) {
+		return fmt.Errorf("effectiveRate must be between 0 and 1")
+	}
+	return nil
+}
+
+// Covers returns true if an on-demand instance of the instance type and operating system in the region and zone is
+// covered by the commitment
+func (c Commitment) Covers(instanceType, operatingSystem, region, zone string) bool {
+	family, size, _ := strings.Cut(instanceType, ".")
+	return family == c.InstanceFamily &&
+		(len(c.Sizes) == 0 || lo.Contains(c.Sizes, size)) &&
+		operatingSystemOrLinux(c.OperatingSystem) == operatingSystemOrLinux(operatingSystem) &&
+		(c.Region == "" || c.Region == region) &&
+		(c.Zone == "" || c.Zone == zone)
+}
+
+// operatingSystemOrLinux defaults an unset operating system to linux
+func operatingSystemOrLinux(operatingSystem string) string {
+	return lo.Ternary(operatingSystem == "", string(v1.Linux), operatingSystem)
+}
+
+// Price returns the effective hourly price of a covered instance with the on-demand price
+func (c Commitment) Price(onDemandPrice float64) float64 {
+	if c.EffectivePrice != nil {
+		return *c.EffectivePrice
+	}
+	return onDemandPrice * lo.FromPtr(c.EffectiveRate)
+}
+
+// Usage is the number of instances that a commitment currently covers
+type Usage struct {
+	Commitment Commitment
+	Used       int
+}
+
+type Provider interface {
+	EffectivePrice(string, string, string, string, float64) (float64, bool)
+	Usage() []Usage
+	SeqNum() uint64
+	Update(context.Context, []*corev1beta1.NodeClaim) error
+}
+
+// DefaultProvider lowers the price of on-demand offerings that are covered by the Savings Plans and Reserved Instances in
+// the pricing commitments file, such as a mounted ConfigMap, so that Karpenter prefers the capacity that's already paid
+// for. Each commitment covers its count of the cluster's on-demand NodeClaims. Offerings are priced at the effective
+// price of a commitment until more NodeClaims use it than it covers, at which point they're priced at the on-demand
+// price again. A commitment that's exactly used up still discounts its offerings, so that consolidation prices the
+// nodes it covers at the effective price and doesn't replace them with instances that are only cheaper at list price.
+type DefaultProvider struct {
+	cm *pretty.ChangeMonitor
+
+	mu          sync.RWMutex
+	commitments []Commitment
+	used        []int
+	// seqNum is a monotonically increasing change counter so that consumers can cheaply detect changes in coverage
+	seqNum uint64
+}
+
+func NewDefaultProvider() *DefaultProvider {
+	return &DefaultProvider{
+		cm: pretty.NewChangeMonitor(),
+	}
+}
+
+// EffectivePrice returns the lowest effective price of the commitments that cover an on-demand instance of the instance
+// type and operating system in the region and zone and aren't overcommitted
+func (p *DefaultProvider) EffectivePrice(instanceType, operatingSystem, region, zone string, onDemandPrice float64) (float64, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	price, ok := 0.0, false
+	for i, c := range p.commitments {
+		if p.used[i] > c.Count || !c.Covers(instanceType, operatingSystem, region, zone) {
+			continue
+		}
+		if committed := c.Price(onDemandPrice); !ok || committed < price {
+			price, ok = committed, true
+		}
+	}
+	return price, ok
+}
+
+// Usage returns the commitments and the number of instances that each covers
+func (p *DefaultProvider) Usage() []Usage {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return lo.Map(p.commitments, func(c Commitment, i int) Usage { return Usage{Commitment: c, Used: p.used[i]} })
+}
+
+// SeqNum returns a counter that is incremented whenever the commitments or their usage change
+func (p *DefaultProvider) SeqNum() uint64 {
+	return atomic.LoadUint64(&p.seqNum)
+}
+
+// Update reloads the pricing commitments file and recomputes their usage from the NodeClaims. The oldest NodeClaims are
+// covered first, by the first commitment in the file that covers them and hasn't been used up.
+func (p *DefaultProvider) Update(ctx context.Context, nodeClaims []*corev1beta1.NodeClaim) error {
+	commitments, err := load(options.FromContext(ctx).PricingCommitmentsFile)
+	if err != nil {
+		return err
+	}
+	nodeClaims = lo.Filter(nodeClaims, func(nc *corev1beta1.NodeClaim, _ int) bool {
+		return nc.Labels[corev1beta1.CapacityTypeLabelKey] == corev1beta1.CapacityTypeOnDemand && nc.Labels[v1.LabelInstanceTypeStable] != ""
+	})
+	sort.SliceStable(nodeClaims, func(i, j int) bool {
+		return nodeClaims[i].CreationTimestamp.Before(&nodeClaims[j].CreationTimestamp)
+	})
+	used := make([]int, len(commitments))
+	for _, nc := range nodeClaims {
+		covering := lo.Filter(lo.Range(len(commitments)), func(i int, _ int) bool {
+			return commitments[i].Covers(nc.Labels[v1.LabelInstanceTypeStable], nc.Labels[v1.LabelOSStable], nc.Labels[v1.LabelTopologyRegion], nc.Labels[v1.LabelTopologyZone])
+		})
+		if len(covering) == 0 {
+			continue
+		}
+		// NodeClaims that every covering commitment is used up for overcommit the first of them
+		i, ok := lo.Find(covering, func(i int) bool { return used[i] < commitments[i].Count })
+		if !ok {
+			i = covering[0]
+		}
+		used[i]++
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.commitments = commitments
+	p.used = used
+	if p.cm.HasChanged("commitment-usage", lo.Map(commitments, func(c Commitment, i int) Usage { return Usage{Commitment: c, Used: used[i]} })) {
+		atomic.AddUint64(&p.seqNum, 1)
+		log.FromContext(ctx).WithValues("usage", lo.SliceToMap(lo.Range(len(commitments)), func(i int) (string, string) {
+			return commitments[i].Name, fmt.Sprintf("%d/%d", used[i], commitments[i].Count)
+		})).V(1).Info("updated pricing commitment usage")
+	}
+	return nil
+}
+
+func load(path string) ([]Commitment, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading pricing commitments, %w", err)
+	}
+	commitments := &Commitments{}
+	if err := yaml.UnmarshalStrict(data, commitments); err != nil {
+		return nil, fmt.Errorf("parsing pricing commitments, %w", err)
+	}
+	names := map[string]struct{}{}
+	for _, c := range commitments.Commitments {
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("validating pricing commitment %q, %w", c.Name, err)
+		}
+		if _, ok := names[c.Name]; ok {
+			return nil, fmt.Errorf("validating pricing commitment %q, name must be unique", c.Name)
+		}
+		names[c.Name] = struct{}{}
+	}
+	return commitments.Commitments, nil
+}
+
+func (p *DefaultProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.commitments = nil
+	p.used = nil
+	p.cm = pretty.NewChangeMonitor()
+	atomic.AddUint64(&p.seqNum, 1)
+}
@@ -98,7 +98,7 @@ func (p *DefaultProvider) Create(ctx context.Context, nodeClass *v1beta1.EC2Node
 	if !schedulingRequirements.HasMinValues() {
 		instanceTypes = p.filterInstanceTypes(nodeClaim, instanceTypes)
 	}
-	instanceTypes, err := cloudprovider.InstanceTypes(instanceTypes).Truncate(schedulingRequirements, maxInstanceTypes)
+	instanceTypes, err := truncateInstanceTypes(nodeClass, schedulingRequirements, instanceTypes)
 	if err != nil {
 		return nil, fmt.Errorf("truncating instance types, %w", err)
 	}
@@ -201,7 +201,7 @@ func (p *DefaultProvider) CreateTags(ctx context.Context, id string, tags map[st
 }
 
 func (p *DefaultProvider) launchInstance(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, tags map[string]string) (*ec2.CreateFleetInstance, error) {
-	capacityType := p.getCapacityType(nodeClaim, instanceTypes)
+	capacityType := p.getCapacityType(nodeClass, nodeClaim, instanceTypes)
 	zonalSubnets, err := p.subnetProvider.ZonalSubnetsForLaunch(ctx, nodeClass, instanceTypes, capacityType)
 	if err != nil {
 		return nil, fmt.Errorf("getting subnets, %w", err)
@@ -212,7 +212,7 @@ func (p *DefaultProvider) launchInstance(ctx context.Context, nodeClass *v1beta1
 	if err != nil {
 		return nil, fmt.Errorf("getting launch template configs, %w", err)
 	}
-	if err := p.checkODFallback(nodeClaim, instanceTypes, launchTemplateConfigs); err != nil {
+	if err := p.checkODFallback(nodeClass, nodeClaim, instanceTypes, launchTemplateConfigs); err != nil {
 		log.FromContext(ctx).Error(err, "failed while checking on-demand fallback")
 	}
 	// Create fleet
@@ -230,9 +230,16 @@ func (p *DefaultProvider) launchInstance(ctx context.Context, nodeClass *v1beta1
 			{ResourceType: aws.String(ec2.ResourceTypeFleet), Tags: utils.MergeTags(tags)},
 		},
 	}
-	if capacityType == corev1beta1.CapacityTypeSpot {
+	// When carbon aware, the overrides are prioritized by their combined cost and carbon score since EC2 would otherwise
+	// choose between them on price alone
+	switch {
+	case capacityType == corev1beta1.CapacityTypeSpot && isCarbonAware(nodeClass):
+		createFleetInput.SpotOptions = &ec2.SpotOptionsRequest{AllocationStrategy: aws.String(ec2.SpotAllocationStrategyCapacityOptimizedPrioritized)}
+	case capacityType == corev1beta1.CapacityTypeSpot:
 		createFleetInput.SpotOptions = &ec2.SpotOptionsRequest{AllocationStrategy: aws.String(ec2.SpotAllocationStrategyPriceCapacityOptimized)}
-	} else {
+	case isCarbonAware(nodeClass):
+		createFleetInput.OnDemandOptions = &ec2.OnDemandOptionsRequest{AllocationStrategy: aws.String(ec2.FleetOnDemandAllocationStrategyPrioritized)}
+	default:
 		createFleetInput.OnDemandOptions = &ec2.OnDemandOptionsRequest{AllocationStrategy: aws.String(ec2.FleetOnDemandAllocationStrategyLowestPrice)}
 	}
 
@@ -268,9 +275,9 @@ func getTags(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *co
 	return lo.Assign(nodeClass.Spec.Tags, staticTags)
 }
 
-func (p *DefaultProvider) checkODFallback(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, launchTemplateConfigs []*ec2.FleetLaunchTemplateConfigRequest) error {
+func (p *DefaultProvider) checkODFallback(nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, launchTemplateConfigs []*ec2.FleetLaunchTemplateConfigRequest) error {
 	// only evaluate for on-demand fallback if the capacity type for the request is OD and both OD and spot are allowed in requirements
-	if p.getCapacityType(nodeClaim, instanceTypes) != corev1beta1.CapacityTypeOnDemand || !scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Get(corev1beta1.CapacityTypeLabelKey).Has(corev1beta1.CapacityTypeSpot) {
+	if p.getCapacityType(nodeClass, nodeClaim, instanceTypes) != corev1beta1.CapacityTypeOnDemand || !scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Get(corev1beta1.CapacityTypeLabelKey).Has(corev1beta1.CapacityTypeSpot) {
 		return nil
 	}
 
@@ -302,7 +309,7 @@ func (p *DefaultProvider) getLaunchTemplateConfigs(ctx context.Context, nodeClas
 	requirements[corev1beta1.CapacityTypeLabelKey] = scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, capacityType)
 	for _, launchTemplate := range launchTemplates {
 		launchTemplateConfig := &ec2.FleetLaunchTemplateConfigRequest{
-			Overrides: p.getOverrides(launchTemplate.InstanceTypes, zonalSubnets, requirements, launchTemplate.ImageID),
+			Overrides: p.getOverrides(launchTemplate.InstanceTypes, zonalSubnets, requirements, launchTemplate.ImageID, isCarbonAware(nodeClass)),
 			LaunchTemplateSpecification: &ec2.FleetLaunchTemplateSpecificationRequest{
 				LaunchTemplateName: aws.String(launchTemplate.Name),
 				Version:            aws.String("$Latest"),
@@ -319,8 +326,10 @@ func (p *DefaultProvider) getLaunchTemplateConfigs(ctx context.Context, nodeClas
 }
 
 // getOverrides creates and returns launch template overrides for the cross product of InstanceTypes and subnets (with subnets being constrained by
-// zones and the offerings in InstanceTypes)
-func (p *DefaultProvider) getOverrides(instanceTypes []*cloudprovider.InstanceType, zonalSubnets map[string]*subnet.Subnet, reqs scheduling.Requirements, image string) []*ec2.FleetLaunchTemplateOverridesRequest {
+// zones and the offerings in InstanceTypes). When prioritized, each override's priority is the price of its offering so
+// that priorities are consistent across launch templates.
+func (p *DefaultProvider) getOverrides(instanceTypes []*cloudprovider.InstanceType, zonalSubnets map[string]*subnet.Subnet, reqs scheduling.Requirements, image string,
+	prioritized bool) []*ec2.FleetLaunchTemplateOverridesRequest {
 	// Unwrap all the offerings to a flat slice that includes a pointer
 	// to the parent instance type name
 	type offeringWithParentName struct {
@@ -346,13 +355,22 @@ func (p *DefaultProvider) getOverrides(instanceTypes []*cloudprovider.InstanceTy
 		if !ok {
 			continue
 		}
-		overrides = append(overrides, &ec2.FleetLaunchTemplateOverridesRequest{
+		override := &ec2.FleetLaunchTemplateOverridesRequest{
 			InstanceType: aws.String(offering.parentInstanceTypeName),
 			SubnetId:     lo.ToPtr(subnet.ID),
 			ImageId:      aws.String(image),
 			// This is technically redundant, but is useful if we have to parse insufficient capacity errors from
 			// CreateFleet so that we can figure out the zone rather than additional API calls to look up the subnet
 			AvailabilityZone: lo.ToPtr(subnet.Zone),
+		}
+		if prioritized {
+			override.Priority = aws.Float64(offering.Price)
+		}
+		overrides = append(overrides, override)
+	}
+	if prioritized {
+		sort.SliceStable(overrides, func(i, j int) bool {
+			return aws.Float64Value(overrides[i].Priority) < aws.Float64Value(overrides[j].Priority)
 		})
 	}
 	return overrides
@@ -368,9 +386,17 @@ func (p *DefaultProvider) updateUnavailableOfferingsCache(ctx context.Context, e
 
 // getCapacityType selects spot if both constraints are flexible and there is an
 // available offering. The AWS Cloud Provider defaults to [ on-demand ], so spot
-// must be explicitly included in capacity type requirements.
-func (p *DefaultProvider) getCapacityType(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) string {
+// must be explicitly included in capacity type requirements. When carbon aware and
+// both are allowed, the capacity type of the offering with the lowest combined cost
+// and carbon score is selected instead, since a cleaner grid can outweigh the
+// discount of spot.
+func (p *DefaultProvider) getCapacityType(nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) string {
 	requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...)
+	if isCarbonAware(nodeClass) && requirements.Get(corev1beta1.CapacityTypeLabelKey).Has(corev1beta1.CapacityTypeOnDemand) {
+		if offering, ok := cheapestCompatibleOffering(requirements, instanceTypes); ok {
+			return offering.Requirements.Get(corev1beta1.CapacityTypeLabelKey).Any()
+		}
+	}
 	if requirements.Get(corev1beta1.CapacityTypeLabelKey).Has(corev1beta1.CapacityTypeSpot) {
 		requirements[corev1beta1.CapacityTypeLabelKey] = scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, corev1beta1.CapacityTypeSpot)
 		for _, instanceType := range instanceTypes {
@@ -384,6 +410,24 @@ func (p *DefaultProvider) getCapacityType(nodeClaim *corev1beta1.NodeClaim, inst
 	return corev1beta1.CapacityTypeOnDemand
 }
 
+// cheapestCompatibleOffering returns the available offering with the lowest price that's compatible with the
+// requirements. Offering prices include the cost of carbon when the EC2NodeClass's carbon policy prices it.
+func cheapestCompatibleOffering(requirements scheduling.Requirements, instanceTypes []*cloudprovider.InstanceType) (cloudprovider.Offering, bool) {
+	var offerings cloudprovider.Offerings
+	for _, instanceType := range instanceTypes {
+		offerings = append(offerings, instanceType.Offerings.Available().Compatible(requirements)...)
+	}
+	if len(offerings) == 0 {
+		return cloudprovider.Offering{}, false
+	}
+	return offerings.Cheapest(), true
+}
+
+// isCarbonAware returns true if the EC2NodeClass's carbon policy affects the choice of offerings
+func isCarbonAware(nodeClass *v1beta1.EC2NodeClass) bool {
+	return nodeClass.Spec.CarbonPolicy != nil && nodeClass.Spec.CarbonPolicy.Mode != v1beta1.CarbonModeOff
+}
+
 // filterInstanceTypes is used to provide filtering on the list of potential instance types to further limit it to those
 // that make the most sense given our specific AWS cloudprovider.
 func (p *DefaultProvider) filterInstanceTypes(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) []*cloudprovider.InstanceType {
@@ -450,6 +494,19 @@ func filterUnwantedSpot(instanceTypes []*cloudprovider.InstanceType) []*cloudpro
 	return instanceTypes
 }
 
+// truncateInstanceTypes limits the instance types to the most that a CreateFleet request accepts. By default, the cheapest
+// instance types are kept. If the EC2NodeClass prefers efficient instance types, the most efficient compatible instance
+// types are kept instead, unless they can't satisfy the minValues of the requirements.
+func truncateInstanceTypes(nodeClass *v1beta1.EC2NodeClass, requirements scheduling.Requirements, instanceTypes []*cloudprovider.InstanceType) ([]*cloudprovider.InstanceType, error) {
+	if isCarbonAware(nodeClass) && lo.FromPtr(nodeClass.Spec.CarbonPolicy.PreferEfficientInstanceTypes) {
+		efficient := lo.Slice(instancetype.OrderByEfficiency(cloudprovider.InstanceTypes(instanceTypes).Compatible(requirements)), 0, maxInstanceTypes)
+		if truncated, err := cloudprovider.InstanceTypes(efficient).Truncate(requirements, maxInstanceTypes); err == nil && len(truncated) > 0 {
+			return truncated, nil
+		}
+	}
+	return cloudprovider.InstanceTypes(instanceTypes).Truncate(requirements, maxInstanceTypes)
+}
+
 // filterExoticInstanceTypes is used to eliminate less desirable instance types (like GPUs) from the list of possible instance types when
 // a set of more appropriate instance types would work. If a set of more desirable instance types is not found, then the original slice
 // of instance types are returned.
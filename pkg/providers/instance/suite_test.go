@@ -23,14 +23,17 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	corecloudprovider "sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/events"
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/operator/scheme"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
 	coretest "sigs.k8s.io/karpenter/pkg/test"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis"
@@ -64,7 +67,7 @@ var _ = BeforeSuite(func() {
 	ctx = options.ToContext(ctx, test.Options())
 	awsEnv = test.NewEnvironment(ctx, env)
 	cloudProvider = cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, events.NewRecorder(&record.FakeRecorder{}),
-		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider)
+		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.CarbonProvider, awsEnv.RegionProvider, clock.RealClock{})
 })
 
 var _ = AfterSuite(func() {
@@ -131,6 +134,91 @@ var _ = Describe("InstanceProvider", func() {
 		Expect(corecloudprovider.IsInsufficientCapacityError(err)).To(BeTrue())
 		Expect(instance).To(BeNil())
 	})
+	Context("Carbon Aware Capacity Type", func() {
+		var instanceTypes []*corecloudprovider.InstanceType
+		BeforeEach(func() {
+			nodeClaim.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{
+					NodeSelectorRequirement: v1.NodeSelectorRequirement{
+						Key:      corev1beta1.CapacityTypeLabelKey,
+						Operator: v1.NodeSelectorOpIn,
+						Values:   []string{corev1beta1.CapacityTypeSpot, corev1beta1.CapacityTypeOnDemand},
+					},
+				},
+			}
+			ExpectApplied(ctx, env.Client, nodeClaim, nodePool, nodeClass)
+			var err error
+			instanceTypes, err = cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			instanceTypes = lo.Filter(instanceTypes, func(i *corecloudprovider.InstanceType, _ int) bool { return i.Name == "m5.xlarge" })
+			Expect(instanceTypes).To(HaveLen(1))
+			// A spot offering in a dirty zone against pricier on-demand offerings, the cheapest of which is in a clean zone
+			instanceTypes[0].Offerings = corecloudprovider.Offerings{
+				offering(corev1beta1.CapacityTypeSpot, "test-zone-1a", 0.30),
+				offering(corev1beta1.CapacityTypeOnDemand, "test-zone-1a", 0.40),
+				offering(corev1beta1.CapacityTypeOnDemand, "test-zone-1b", 0.35),
+			}
+		})
+		It("should launch the capacity type of the offering with the lowest combined cost and carbon score", func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+			instanceTypes[0].Offerings[0].Price = 0.50
+			_, err := awsEnv.InstanceProvider.Create(ctx, nodeClass, nodeClaim, instanceTypes)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			createFleetInput := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(createFleetInput.TargetCapacitySpecification.DefaultTargetCapacityType)).To(Equal(corev1beta1.CapacityTypeOnDemand))
+			Expect(aws.StringValue(createFleetInput.OnDemandOptions.AllocationStrategy)).To(Equal(ec2.FleetOnDemandAllocationStrategyPrioritized))
+		})
+		It("should prioritize overrides by their combined cost and carbon score", func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+			instanceTypes[0].Offerings[0].Price = 0.50
+			_, err := awsEnv.InstanceProvider.Create(ctx, nodeClass, nodeClaim, instanceTypes)
+			Expect(err).ToNot(HaveOccurred())
+
+			createFleetInput := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			overrides := lo.FlatMap(createFleetInput.LaunchTemplateConfigs, func(ltc *ec2.FleetLaunchTemplateConfigRequest, _ int) []*ec2.FleetLaunchTemplateOverridesRequest {
+				return ltc.Overrides
+			})
+			Expect(overrides).To(HaveLen(2))
+			Expect(aws.StringValue(overrides[0].AvailabilityZone)).To(Equal("test-zone-1b"))
+			Expect(aws.Float64Value(overrides[0].Priority)).To(BeNumerically("==", 0.35))
+			Expect(aws.StringValue(overrides[1].AvailabilityZone)).To(Equal("test-zone-1a"))
+			Expect(aws.Float64Value(overrides[1].Priority)).To(BeNumerically("==", 0.40))
+		})
+		It("should use the capacity optimized prioritized strategy when spot has the lowest combined score", func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+			_, err := awsEnv.InstanceProvider.Create(ctx, nodeClass, nodeClaim, instanceTypes)
+			Expect(err).ToNot(HaveOccurred())
+
+			createFleetInput := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(createFleetInput.TargetCapacitySpecification.DefaultTargetCapacityType)).To(Equal(corev1beta1.CapacityTypeSpot))
+			Expect(aws.StringValue(createFleetInput.SpotOptions.AllocationStrategy)).To(Equal(ec2.SpotAllocationStrategyCapacityOptimizedPrioritized))
+		})
+		It("should prefer spot and not prioritize overrides without a carbon policy", func() {
+			instanceTypes[0].Offerings[0].Price = 0.50
+			_, err := awsEnv.InstanceProvider.Create(ctx, nodeClass, nodeClaim, instanceTypes)
+			Expect(err).ToNot(HaveOccurred())
+
+			createFleetInput := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(createFleetInput.TargetCapacitySpecification.DefaultTargetCapacityType)).To(Equal(corev1beta1.CapacityTypeSpot))
+			Expect(aws.StringValue(createFleetInput.SpotOptions.AllocationStrategy)).To(Equal(ec2.SpotAllocationStrategyPriceCapacityOptimized))
+			for _, ltc := range createFleetInput.LaunchTemplateConfigs {
+				for _, override := range ltc.Overrides {
+					Expect(override.Priority).To(BeNil())
+				}
+			}
+		})
+		It("should prefer spot when carbon mode is off", func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeOff, CarbonPrice: lo.ToPtr[int64](100)}
+			instanceTypes[0].Offerings[0].Price = 0.50
+			_, err := awsEnv.InstanceProvider.Create(ctx, nodeClass, nodeClaim, instanceTypes)
+			Expect(err).ToNot(HaveOccurred())
+
+			createFleetInput := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(createFleetInput.TargetCapacitySpecification.DefaultTargetCapacityType)).To(Equal(corev1beta1.CapacityTypeSpot))
+		})
+	})
 	It("should return all NodePool-owned instances from List", func() {
 		ids := sets.New[string]()
 		// Provision instances that have the karpenter.sh/nodepool key
@@ -200,3 +288,14 @@ var _ = Describe("InstanceProvider", func() {
 		Expect(ids.Equal(retrievedIDs)).To(BeTrue())
 	})
 })
+
+func offering(capacityType, zone string, price float64) corecloudprovider.Offering {
+	return corecloudprovider.Offering{
+		Requirements: scheduling.NewRequirements(
+			scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, capacityType),
+			scheduling.NewRequirement(v1.LabelTopologyZone, v1.NodeSelectorOpIn, zone),
+		),
+		Price:     price,
+		Available: true,
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype
+
+import (
+	"sort"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+)
+
+const (
+	// generationPerformanceGain is the assumed increase in the throughput of a vCPU with each instance generation
+	generationPerformanceGain = 0.15
+	// baselineGeneration is the instance generation that performance is relative to
+	baselineGeneration = 5
+)
+
+// cpuManufacturerPerformance is the relative throughput of a vCPU for each value of the cpu manufacturer label. Graviton
+// vCPUs are physical cores, while Intel and AMD vCPUs are hyperthreads that share a core.
+var cpuManufacturerPerformance = map[string]float64{
+	"aws":   1.2,
+	"amd":   1.0,
+	"intel": 1.0,
+}
+
+// EfficiencyScore estimates the work an instance type does per watt, as the relative throughput of its vCPUs, adjusted
+// for the instance generation and cpu manufacturer, divided by the draw from ComputePower at the average utilization.
+// Scores are only meaningful relative to each other. Instance types whose labels don't describe their CPU and memory
+// score 0.
+func EfficiencyScore(instanceType *cloudprovider.InstanceType) float64 {
+	labels := map[string]string{}
+	for key, requirement := range instanceType.Requirements {
+		if requirement.Operator() == v1.NodeSelectorOpIn && requirement.Len() == 1 {
+			labels[key] = requirement.Any()
+		}
+	}
+	info, ok := InstanceTypeInfoFromLabels(labels)
+	if !ok {
+		return 0
+	}
+	watts := ComputePower(info).Watts(AverageUtilization)
+	if watts <= 0 {
+		return 0
+	}
+	performance := float64(*info.VCpuInfo.DefaultVCpus)
+	if generation, err := strconv.Atoi(labels[v1beta1.LabelInstanceGeneration]); err == nil {
+		performance *= 1 + generationPerformanceGain*float64(generation-baselineGeneration)
+	}
+	if factor, ok := cpuManufacturerPerformance[labels[v1beta1.LabelInstanceCPUManufacturer]]; ok {
+		performance *= factor
+	}
+	return performance / watts
+}
+
+// OrderByEfficiency returns a copy of the instance types ordered from the highest efficiency score to the lowest
+func OrderByEfficiency(instanceTypes []*cloudprovider.InstanceType) []*cloudprovider.InstanceType {
+	scores := make(map[string]float64, len(instanceTypes))
+	for _, it := range instanceTypes {
+		scores[it.Name] = EfficiencyScore(it)
+	}
+	ordered := append([]*cloudprovider.InstanceType{}, instanceTypes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if scores[ordered[i].Name] == scores[ordered[j].Name] {
+			return ordered[i].Name < ordered[j].Name
+		}
+		return scores[ordered[i].Name] > scores[ordered[j].Name]
+	})
+	return ordered
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -40,6 +41,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/commitment"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 
@@ -52,13 +55,16 @@ type Provider interface {
 	List(context.Context, *corev1beta1.KubeletConfiguration, *v1beta1.EC2NodeClass) ([]*cloudprovider.InstanceType, error)
 	UpdateInstanceTypes(ctx context.Context) error
 	UpdateInstanceTypeOfferings(ctx context.Context) error
+	EstimatedEmissions(string, string, bool) (float64, bool)
 }
 
 type DefaultProvider struct {
-	region          string
-	ec2api          ec2iface.EC2API
-	subnetProvider  subnet.Provider
-	pricingProvider pricing.Provider
+	region             string
+	ec2api             ec2iface.EC2API
+	subnetProvider     subnet.Provider
+	pricingProvider    pricing.Provider
+	carbonProvider     carbon.Provider
+	commitmentProvider commitment.Provider
 
 	// Values stored *before* considering insufficient capacity errors from the unavailableOfferings cache.
 	// Fully initialized Instance Types are also cached based on the set of all instance types, zones, unavailableOfferings cache,
@@ -67,6 +73,9 @@ type DefaultProvider struct {
 	muInstanceTypeInfo sync.RWMutex
 	// TODO @engedaam: Look into only storing the needed EC2InstanceTypeInfo
 	instanceTypesInfo []*ec2.InstanceTypeInfo
+	// instanceTypeHosts is the largest instance type of each instance family, which approximates the physical host that
+	// instances of the family share when amortizing embodied emissions
+	instanceTypeHosts map[string]*ec2.InstanceTypeInfo
 
 	muInstanceTypeOfferings sync.RWMutex
 	instanceTypeOfferings   map[string]sets.Set[string]
@@ -82,13 +91,17 @@ type DefaultProvider struct {
 }
 
 func NewDefaultProvider(region string, instanceTypesCache *cache.Cache, ec2api ec2iface.EC2API, subnetProvider subnet.Provider,
-	unavailableOfferingsCache *awscache.UnavailableOfferings, pricingProvider pricing.Provider) *DefaultProvider {
+	unavailableOfferingsCache *awscache.UnavailableOfferings, pricingProvider pricing.Provider, carbonProvider carbon.Provider,
+	commitmentProvider commitment.Provider) *DefaultProvider {
 	return &DefaultProvider{
 		ec2api:                ec2api,
 		region:                region,
 		subnetProvider:        subnetProvider,
 		pricingProvider:       pricingProvider,
+		carbonProvider:        carbonProvider,
+		commitmentProvider:    commitmentProvider,
 		instanceTypesInfo:     []*ec2.InstanceTypeInfo{},
+		instanceTypeHosts:     map[string]*ec2.InstanceTypeInfo{},
 		instanceTypeOfferings: map[string]sets.Set[string]{},
 		instanceTypesCache:    instanceTypesCache,
 		unavailableOfferings:  unavailableOfferingsCache,
@@ -124,13 +137,18 @@ func (p *DefaultProvider) List(ctx context.Context, kc *corev1beta1.KubeletConfi
 	subnetZonesHash, _ := hashstructure.Hash(subnetZones, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	kcHash, _ := hashstructure.Hash(kc, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	blockDeviceMappingsHash, _ := hashstructure.Hash(nodeClass.Spec.BlockDeviceMappings, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
-	key := fmt.Sprintf("%d-%d-%d-%016x-%016x-%016x-%s-%s",
+	carbonPolicyHash, _ := hashstructure.Hash(nodeClass.Spec.CarbonPolicy, hashstructure.FormatV2, nil)
+	key := fmt.Sprintf("%d-%d-%d-%d-%d-%d-%016x-%016x-%016x-%016x-%s-%s",
 		p.instanceTypesSeqNum,
 		p.instanceTypeOfferingsSeqNum,
 		p.unavailableOfferings.SeqNum,
+		p.carbonProvider.SeqNum(),
+		p.commitmentProvider.SeqNum(),
+		p.pricingProvider.OverridesSeqNum(),
 		subnetZonesHash,
 		kcHash,
 		blockDeviceMappingsHash,
+		carbonPolicyHash,
 		aws.StringValue((*string)(nodeClass.Spec.InstanceStorePolicy)),
 		aws.StringValue(nodeClass.Spec.AMIFamily),
 	)
@@ -152,6 +170,11 @@ func (p *DefaultProvider) List(ctx context.Context, kc *corev1beta1.KubeletConfi
 		log.FromContext(ctx).WithValues("zones", allZones.UnsortedList()).V(1).Info("discovered zones")
 	}
 	amiFamily := amifamily.GetAMIFamily(nodeClass.Spec.AMIFamily, &amifamily.Options{})
+	// Windows AMI families are billed for their license, so their offerings are priced from the Windows price list
+	operatingSystem := string(v1.Linux)
+	if _, ok := amiFamily.(*amifamily.Windows); ok {
+		operatingSystem = string(v1.Windows)
+	}
 	result := lo.Map(p.instanceTypesInfo, func(i *ec2.InstanceTypeInfo, _ int) *cloudprovider.InstanceType {
 		instanceTypeVCPU.With(prometheus.Labels{
 			instanceTypeLabel: *i.InstanceType,
@@ -167,7 +190,8 @@ func (p *DefaultProvider) List(ctx context.Context, kc *corev1beta1.KubeletConfi
 		return NewInstanceType(ctx, i, p.region,
 			nodeClass.Spec.BlockDeviceMappings, nodeClass.Spec.InstanceStorePolicy,
 			kc.MaxPods, kc.PodsPerCore, kc.KubeReserved, kc.SystemReserved, kc.EvictionHard, kc.EvictionSoft,
-			amiFamily, p.createOfferings(ctx, i, allZones, p.instanceTypeOfferings[aws.StringValue(i.InstanceType)], nodeClass.Status.Subnets),
+			amiFamily, p.createOfferings(ctx, i, allZones, p.instanceTypeOfferings[aws.StringValue(i.InstanceType)], nodeClass.Status.Subnets, operatingSystem, nodeClass.Spec.CarbonPolicy),
+			p.carbonProvider.EmbodiedEmissions(i, p.instanceTypeHosts[instanceFamily(i)]),
 		)
 	})
 	p.instanceTypesCache.SetDefault(key, result)
@@ -178,7 +202,10 @@ func (p *DefaultProvider) LivenessProbe(req *http.Request) error {
 	if err := p.subnetProvider.LivenessProbe(req); err != nil {
 		return err
 	}
-	return p.pricingProvider.LivenessProbe(req)
+	if err := p.pricingProvider.LivenessProbe(req); err != nil {
+		return err
+	}
+	return p.carbonProvider.LivenessProbe(req)
 }
 
 func (p *DefaultProvider) UpdateInstanceTypes(ctx context.Context) error {
@@ -216,9 +243,26 @@ func (p *DefaultProvider) UpdateInstanceTypes(ctx context.Context) error {
 			"count", len(instanceTypes)).V(1).Info("discovered instance types")
 	}
 	p.instanceTypesInfo = instanceTypes
+	p.instanceTypeHosts = instanceTypeHosts(instanceTypes)
 	return nil
 }
 
+// instanceTypeHosts returns the instance type with the most vCPUs for each instance family
+func instanceTypeHosts(instanceTypes []*ec2.InstanceTypeInfo) map[string]*ec2.InstanceTypeInfo {
+	hosts := map[string]*ec2.InstanceTypeInfo{}
+	for _, info := range instanceTypes {
+		family := instanceFamily(info)
+		if host, ok := hosts[family]; !ok || aws.Int64Value(info.VCpuInfo.DefaultVCpus) > aws.Int64Value(host.VCpuInfo.DefaultVCpus) {
+			hosts[family] = info
+		}
+	}
+	return hosts
+}
+
+func instanceFamily(info *ec2.InstanceTypeInfo) string {
+	return strings.Split(aws.StringValue(info.InstanceType), ".")[0]
+}
+
 func (p *DefaultProvider) UpdateInstanceTypeOfferings(ctx context.Context) error {
 	// DO NOT REMOVE THIS LOCK ----------------------------------------------------------------------------
 	// We lock here so that multiple callers to GetInstanceTypes do not result in cache misses and multiple
@@ -254,15 +298,23 @@ func (p *DefaultProvider) UpdateInstanceTypeOfferings(ctx context.Context) error
 
 // createOfferings creates a set of mutually exclusive offerings for a given instance type. This provider maintains an
 // invariant that each offering is mutually exclusive. Specifically, there is an offering for each permutation of zone
-// and capacity type. ZoneID is also injected into the offering requirements, when available, but there is a 1-1
-// mapping between zone and zoneID so this does not change the number of offerings.
+// and capacity type. The region, ZoneID and the zone's carbon tier are also injected into the offering requirements,
+// when available, but each zone has a single region, zoneID and carbon tier so this does not change the number of
+// offerings. The region lets offerings from providers for different regions be told apart once they're combined.
+//
+// When the carbon policy sets a carbon price, the estimated emissions of each offering, priced at that rate, are added to
+// its price so that every consumer of offering prices weighs carbon against cost without needing to know about it. In
+// Strict mode, offerings in zones above the maximum intensity are marked unavailable, the same as offerings that have
+// seen an insufficient capacity error.
 //
 // Each requirement on the offering is guaranteed to have a single value. To get the value for a requirement on an
 // offering, you can do the following thanks to this invariant:
 //
 //	offering.Requirements.Get(v1.TopologyLabelZone).Any()
-func (p *DefaultProvider) createOfferings(ctx context.Context, instanceType *ec2.InstanceTypeInfo, zones, instanceTypeZones sets.Set[string], subnets []v1beta1.Subnet) []cloudprovider.Offering {
+func (p *DefaultProvider) createOfferings(ctx context.Context, instanceType *ec2.InstanceTypeInfo, zones, instanceTypeZones sets.Set[string],
+	subnets []v1beta1.Subnet, operatingSystem string, carbonPolicy *v1beta1.CarbonPolicy) []cloudprovider.Offering {
 	var offerings []cloudprovider.Offering
+	power := ComputePower(instanceType)
 	for zone := range zones {
 		// while usage classes should be a distinct set, there's no guarantee of that
 		for capacityType := range sets.NewString(aws.StringValueSlice(instanceType.SupportedUsageClasses)...) {
@@ -272,9 +324,13 @@ func (p *DefaultProvider) createOfferings(ctx context.Context, instanceType *ec2
 			var ok bool
 			switch capacityType {
 			case ec2.UsageClassTypeSpot:
-				price, ok = p.pricingProvider.SpotPrice(*instanceType.InstanceType, zone)
+				price, ok = p.pricingProvider.SpotPriceForOS(*instanceType.InstanceType, operatingSystem, zone)
 			case ec2.UsageClassTypeOnDemand:
-				price, ok = p.pricingProvider.OnDemandPrice(*instanceType.InstanceType)
+				price, ok = p.pricingProvider.OnDemandPriceForOS(*instanceType.InstanceType, operatingSystem)
+				// instances covered by a Savings Plan or Reserved Instance are effectively billed below the on-demand price
+				if committed, covered := p.commitmentProvider.EffectivePrice(*instanceType.InstanceType, operatingSystem, p.region, zone, price); ok && covered {
+					price = committed
+				}
 			case "capacity-block":
 				// ignore since karpenter doesn't support it yet, but do not log an unknown capacity type error
 				continue
@@ -286,18 +342,22 @@ func (p *DefaultProvider) createOfferings(ctx context.Context, instanceType *ec2
 			subnet, hasSubnet := lo.Find(subnets, func(s v1beta1.Subnet) bool {
 				return s.Zone == zone
 			})
-			available := !isUnavailable && ok && instanceTypeZones.Has(zone) && hasSubnet
+			available := !isUnavailable && ok && instanceTypeZones.Has(zone) && hasSubnet && !p.exceedsCarbonCeiling(zone, carbonPolicy)
 			offering := cloudprovider.Offering{
 				Requirements: scheduling.NewRequirements(
 					scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, capacityType),
 					scheduling.NewRequirement(v1.LabelTopologyZone, v1.NodeSelectorOpIn, zone),
+					scheduling.NewRequirement(v1.LabelTopologyRegion, v1.NodeSelectorOpIn, p.region),
 				),
-				Price:     price,
+				Price:     price + p.carbonCost(instanceType, power, zone, carbonPolicy),
 				Available: available,
 			}
 			if subnet.ZoneID != "" {
 				offering.Requirements.Add(scheduling.NewRequirement(v1beta1.LabelTopologyZoneID, v1.NodeSelectorOpIn, subnet.ZoneID))
 			}
+			if intensity, ok := p.carbonProvider.ZoneIntensity(zone); ok {
+				offering.Requirements.Add(scheduling.NewRequirement(v1beta1.LabelTopologyZoneCarbonTier, v1.NodeSelectorOpIn, carbon.Tier(intensity)))
+			}
 			offerings = append(offerings, offering)
 			instanceTypeOfferingAvailable.With(prometheus.Labels{
 				instanceTypeLabel: *instanceType.InstanceType,
@@ -309,13 +369,81 @@ func (p *DefaultProvider) createOfferings(ctx context.Context, instanceType *ec2
 				capacityTypeLabel: capacityType,
 				zoneLabel:         zone,
 			}).Set(price)
+			if gramsPerHour, ok := p.estimatedGramsPerHour(power, zone); ok {
+				instanceTypeOfferingCarbonEstimate.With(prometheus.Labels{
+					instanceTypeLabel: *instanceType.InstanceType,
+					capacityTypeLabel: capacityType,
+					zoneLabel:         zone,
+				}).Set(gramsPerHour)
+			}
 		}
 	}
 	return offerings
 }
 
+// carbonCost returns the hourly cost of the estimated emissions of an instance type in a zone, priced at the carbon price
+// of the carbon policy. Embodied emissions are only included when the carbon policy asks for them.
+func (p *DefaultProvider) carbonCost(instanceType *ec2.InstanceTypeInfo, power Power, zone string, carbonPolicy *v1beta1.CarbonPolicy) float64 {
+	if carbonPolicy == nil || carbonPolicy.Mode == v1beta1.CarbonModeOff || lo.FromPtr(carbonPolicy.CarbonPrice) == 0 {
+		return 0
+	}
+	gramsPerHour, ok := p.estimatedEmissions(instanceType, power, zone, lo.FromPtr(carbonPolicy.IncludeEmbodied))
+	if !ok {
+		return 0
+	}
+	// carbon price is in $/tCO2e, so convert grams to metric tons
+	return float64(lo.FromPtr(carbonPolicy.CarbonPrice)) * gramsPerHour / 1e6
+}
+
+// EstimatedEmissions returns the estimated emissions, in gCO2e/hour, of running an instance type in a zone, optionally
+// including its share of the embodied emissions of its host
+func (p *DefaultProvider) EstimatedEmissions(instanceType string, zone string, includeEmbodied bool) (float64, bool) {
+	p.muInstanceTypeInfo.RLock()
+	defer p.muInstanceTypeInfo.RUnlock()
+	info, ok := lo.Find(p.instanceTypesInfo, func(i *ec2.InstanceTypeInfo) bool {
+		return aws.StringValue(i.InstanceType) == instanceType
+	})
+	if !ok {
+		return 0, false
+	}
+	return p.estimatedEmissions(info, ComputePower(info), zone, includeEmbodied)
+}
+
+func (p *DefaultProvider) estimatedEmissions(instanceType *ec2.InstanceTypeInfo, power Power, zone string, includeEmbodied bool) (float64, bool) {
+	gramsPerHour, ok := p.estimatedGramsPerHour(power, zone)
+	if !ok {
+		return 0, false
+	}
+	if includeEmbodied {
+		gramsPerHour += p.carbonProvider.EmbodiedEmissions(instanceType, p.instanceTypeHosts[instanceFamily(instanceType)]).GramsPerHour
+	}
+	return gramsPerHour, true
+}
+
+// exceedsCarbonCeiling returns true if the carbon policy is Strict and the grid intensity of the zone is above its
+// maximum intensity. Zones without any intensity data are treated as exceeding the ceiling, since we can't prove that
+// they're below it.
+func (p *DefaultProvider) exceedsCarbonCeiling(zone string, carbonPolicy *v1beta1.CarbonPolicy) bool {
+	if carbonPolicy == nil || carbonPolicy.Mode != v1beta1.CarbonModeStrict || carbonPolicy.MaxIntensity == nil {
+		return false
+	}
+	intensity, ok := p.carbonProvider.ZoneIntensity(zone)
+	return !ok || intensity > float64(*carbonPolicy.MaxIntensity)
+}
+
+// estimatedGramsPerHour estimates the operational emissions, in gCO2e/hour, of running an instance type in a zone at
+// the average utilization, including the overhead of the data center's cooling and power distribution
+func (p *DefaultProvider) estimatedGramsPerHour(power Power, zone string) (float64, bool) {
+	intensity, ok := p.carbonProvider.ZoneIntensity(zone)
+	if !ok {
+		return 0, false
+	}
+	return OperationalGramsPerHour(power, p.region, intensity), true
+}
+
 func (p *DefaultProvider) Reset() {
 	p.instanceTypesInfo = []*ec2.InstanceTypeInfo{}
+	p.instanceTypeHosts = map[string]*ec2.InstanceTypeInfo{}
 	p.instanceTypeOfferings = map[string]sets.Set[string]{}
 	p.instanceTypesCache.Flush()
 }
@@ -76,8 +76,20 @@ var (
 			capacityTypeLabel,
 			zoneLabel,
 		})
+	instanceTypeOfferingCarbonEstimate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: cloudProviderSubsystem,
+			Name:      "instance_type_offering_carbon_estimate",
+			Help:      "Instance type offering estimated hourly operational emissions, in grams of CO2 equivalent, at the average utilization and the current grid intensity of the zone, based on instance type, capacity type, and zone.",
+		},
+		[]string{
+			instanceTypeLabel,
+			capacityTypeLabel,
+			zoneLabel,
+		})
 )
 
 func init() {
-	crmetrics.Registry.MustRegister(instanceTypeVCPU, instanceTypeMemory, instanceTypeOfferingAvailable, instanceTypeOfferingPriceEstimate)
+	crmetrics.Registry.MustRegister(instanceTypeVCPU, instanceTypeMemory, instanceTypeOfferingAvailable, instanceTypeOfferingPriceEstimate, instanceTypeOfferingCarbonEstimate)
 }
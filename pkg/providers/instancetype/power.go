@@ -0,0 +1,153 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+)
+
+const (
+	// AverageUtilization is the CPU utilization assumed when estimating the draw of a running instance
+	AverageUtilization = 0.5
+	// memoryWattsPerGiB is the draw of memory, which doesn't vary meaningfully with utilization
+	memoryWattsPerGiB = 0.392
+	// storageWattsPerTB is the draw of local SSD storage
+	storageWattsPerTB = 1.2
+)
+
+// defaultAcceleratorWatts is used for GPUs and accelerators that aren't in the generated table
+var defaultAcceleratorWatts = Power{IdleWatts: 35, MaxWatts: 250}
+
+// Power is the estimated electrical draw of an instance type when idle and when fully utilized
+type Power struct {
+	IdleWatts float64
+	MaxWatts  float64
+}
+
+// Watts returns the estimated draw at a utilization between 0 and 1 by interpolating between idle and max
+func (p Power) Watts(utilization float64) float64 {
+	return p.IdleWatts + lo.Clamp(utilization, 0, 1)*(p.MaxWatts-p.IdleWatts)
+}
+
+func (p Power) add(other Power, count float64) Power {
+	return Power{IdleWatts: p.IdleWatts + other.IdleWatts*count, MaxWatts: p.MaxWatts + other.MaxWatts*count}
+}
+
+// ComputePower estimates the draw of an instance type from its vCPUs, memory, GPUs, accelerators and local storage
+// following the Cloud Carbon Footprint methodology. CPU coefficients are looked up by instance family and fall back to a
+// coefficient chosen from the processor manufacturer and architecture for families we don't know about.
+func ComputePower(info *ec2.InstanceTypeInfo) Power {
+	p := Power{}.add(cpuWatts(info), float64(lo.FromPtr(info.VCpuInfo.DefaultVCpus)))
+	memoryWatts := float64(lo.FromPtr(info.MemoryInfo.SizeInMiB)) / 1024 * memoryWattsPerGiB
+	p = p.add(Power{IdleWatts: memoryWatts, MaxWatts: memoryWatts}, 1)
+	if info.InstanceStorageInfo != nil {
+		storageWatts := float64(lo.FromPtr(info.InstanceStorageInfo.TotalSizeInGB)) / 1000 * storageWattsPerTB
+		p = p.add(Power{IdleWatts: storageWatts, MaxWatts: storageWatts}, 1)
+	}
+	if info.GpuInfo != nil {
+		for _, gpu := range info.GpuInfo.Gpus {
+			p = p.add(acceleratorWatts(lo.FromPtr(gpu.Name)), float64(lo.FromPtr(gpu.Count)))
+		}
+	}
+	if info.InferenceAcceleratorInfo != nil {
+		for _, accelerator := range info.InferenceAcceleratorInfo.Accelerators {
+			p = p.add(acceleratorWatts(lo.FromPtr(accelerator.Name)), float64(lo.FromPtr(accelerator.Count)))
+		}
+	} else if strings.HasPrefix(lo.FromPtr(info.InstanceType), "trn1") {
+		p = p.add(acceleratorWatts("Trainium"), float64(awsNeurons(info).Value()))
+	}
+	return p
+}
+
+func cpuWatts(info *ec2.InstanceTypeInfo) Power {
+	if p, ok := InstanceFamilyCPUWatts[strings.Split(lo.FromPtr(info.InstanceType), ".")[0]]; ok {
+		return p
+	}
+	manufacturer := ""
+	if info.ProcessorInfo != nil {
+		manufacturer = lo.FromPtr(info.ProcessorInfo.Manufacturer)
+	}
+	switch {
+	case getArchitecture(info) == "arm64":
+		return MicroarchitectureCPUWatts["Graviton2"]
+	case manufacturer == "AMD":
+		return MicroarchitectureCPUWatts["AMD EPYC 2nd Gen"]
+	case manufacturer == "Intel":
+		return MicroarchitectureCPUWatts["Cascade Lake"]
+	default:
+		return MicroarchitectureCPUWatts["Average"]
+	}
+}
+
+func acceleratorWatts(name string) Power {
+	if p, ok := AcceleratorWatts[lowerKabobCase(name)]; ok {
+		return p
+	}
+	return defaultAcceleratorWatts
+}
+
+// OperationalGramsPerHour estimates the operational emissions, in gCO2e/hour, of an instance type's draw at the average
+// utilization on a grid with the given intensity, including the overhead of the region's data centers
+func OperationalGramsPerHour(power Power, region string, intensity float64) float64 {
+	return power.Watts(AverageUtilization) / 1000 * carbon.PUE(region) * intensity
+}
+
+// cpuManufacturers maps the values of the cpu manufacturer label back to the manufacturers that EC2 reports
+var cpuManufacturers = map[string]string{"intel": "Intel", "amd": "AMD", "aws": "AWS"}
+
+// InstanceTypeInfoFromLabels reconstructs the parts of an instance type's description that ComputePower uses from the
+// well-known labels of a node or NodeClaim, so that the power draw of existing capacity can be estimated without the
+// EC2 API. It returns false if the labels don't describe the instance type's CPU and memory.
+func InstanceTypeInfoFromLabels(labels map[string]string) (*ec2.InstanceTypeInfo, bool) {
+	vcpus, err := strconv.ParseInt(labels[v1beta1.LabelInstanceCPU], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	memory, err := strconv.ParseInt(labels[v1beta1.LabelInstanceMemory], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	info := &ec2.InstanceTypeInfo{
+		InstanceType: aws.String(labels[v1.LabelInstanceTypeStable]),
+		VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(vcpus)},
+		MemoryInfo:   &ec2.MemoryInfo{SizeInMiB: aws.Int64(memory)},
+		ProcessorInfo: &ec2.ProcessorInfo{
+			Manufacturer:           aws.String(cpuManufacturers[labels[v1beta1.LabelInstanceCPUManufacturer]]),
+			SupportedArchitectures: aws.StringSlice([]string{lo.Invert(v1beta1.AWSToKubeArchitectures)[labels[v1.LabelArchStable]]}),
+		},
+	}
+	if nvme, err := strconv.ParseInt(labels[v1beta1.LabelInstanceLocalNVME], 10, 64); err == nil {
+		info.InstanceStorageInfo = &ec2.InstanceStorageInfo{TotalSizeInGB: aws.Int64(nvme)}
+	}
+	if count, err := strconv.ParseInt(labels[v1beta1.LabelInstanceGPUCount], 10, 64); err == nil {
+		info.GpuInfo = &ec2.GpuInfo{Gpus: []*ec2.GpuDeviceInfo{{Name: aws.String(labels[v1beta1.LabelInstanceGPUName]), Count: aws.Int64(count)}}}
+	}
+	// Trn1 instance types are labeled with Inferentia accelerators, so we leave ComputePower to count their Trainium chips
+	if count, err := strconv.ParseInt(labels[v1beta1.LabelInstanceAcceleratorCount], 10, 64); err == nil && !strings.HasPrefix(labels[v1.LabelInstanceTypeStable], "trn1") {
+		info.InferenceAcceleratorInfo = &ec2.InferenceAcceleratorInfo{Accelerators: []*ec2.InferenceDeviceInfo{
+			{Name: aws.String(labels[v1beta1.LabelInstanceAcceleratorName]), Count: aws.Int64(count)},
+		}}
+	}
+	return info, true
+}
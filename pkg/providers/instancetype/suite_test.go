@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
@@ -28,6 +30,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	awspricing "github.com/aws/aws-sdk-go/service/pricing"
 	"github.com/awslabs/operatorpkg/status"
 	"github.com/imdario/mergo"
 	. "github.com/onsi/ginkgo/v2"
@@ -35,6 +38,7 @@ import (
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	clock "k8s.io/utils/clock/testing"
@@ -60,7 +64,9 @@ import (
 	"github.com/aws/karpenter-provider-aws/pkg/fake"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
 )
 
@@ -85,7 +91,7 @@ var _ = BeforeSuite(func() {
 	awsEnv = test.NewEnvironment(ctx, env)
 	fakeClock = &clock.FakeClock{}
 	cloudProvider = cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, events.NewRecorder(&record.FakeRecorder{}),
-		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider)
+		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.CarbonProvider, awsEnv.RegionProvider, fakeClock)
 	cluster = state.NewCluster(fakeClock, env.Client, cloudProvider)
 	prov = provisioning.NewProvisioner(env.Client, events.NewRecorder(&record.FakeRecorder{}), cloudProvider, cluster)
 })
@@ -245,6 +251,9 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1beta1.LabelInstanceAcceleratorManufacturer:      "aws",
 			v1beta1.LabelInstanceAcceleratorCount:             "1",
 			v1beta1.LabelTopologyZoneID:                       "tstz1-1a",
+			v1beta1.LabelTopologyZoneCarbonTier:               "medium",
+			v1beta1.LabelInstanceWattsMax:                     "248",
+			v1beta1.LabelInstanceEmbodiedKgCO2e:               "1305",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -297,6 +306,9 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1beta1.LabelInstanceGPUMemory:                    "16384",
 			v1beta1.LabelInstanceLocalNVME:                    "900",
 			v1beta1.LabelTopologyZoneID:                       "tstz1-1a",
+			v1beta1.LabelTopologyZoneCarbonTier:               "medium",
+			v1beta1.LabelInstanceWattsMax:                     "248",
+			v1beta1.LabelInstanceEmbodiedKgCO2e:               "1305",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -348,6 +360,9 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1beta1.LabelInstanceAcceleratorManufacturer:      "aws",
 			v1beta1.LabelInstanceAcceleratorCount:             "1",
 			v1beta1.LabelTopologyZoneID:                       "tstz1-1a",
+			v1beta1.LabelTopologyZoneCarbonTier:               "medium",
+			v1beta1.LabelInstanceWattsMax:                     "113",
+			v1beta1.LabelInstanceEmbodiedKgCO2e:               "348",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -816,6 +831,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 				amiFamily,
 				nil,
+				carbon.Embodied{},
 			)
 			Expect(it.Capacity.Pods().Value()).ToNot(BeNumerically("==", 110))
 		}
@@ -839,6 +855,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 				amiFamily,
 				nil,
+				carbon.Embodied{},
 			)
 			Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 110))
 		}
@@ -908,6 +925,26 @@ var _ = Describe("InstanceTypeProvider", func() {
 				}
 			}
 		})
+		It("should expose carbon metrics for instance types", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(len(instanceTypes)).To(BeNumerically(">", 0))
+			for _, it := range instanceTypes {
+				for _, of := range it.Offerings {
+					zone := of.Requirements.Get(v1.LabelTopologyZone).Any()
+					metric, ok := FindMetricWithLabelValues("karpenter_cloudprovider_instance_type_offering_carbon_estimate", map[string]string{
+						"instance_type": it.Name,
+						"capacity_type": of.Requirements.Get(corev1beta1.CapacityTypeLabelKey).Any(),
+						"zone":          zone,
+					})
+					Expect(ok).To(BeTrue())
+					Expect(metric).To(Not(BeNil()))
+					gramsPerHour, ok := awsEnv.InstanceTypesProvider.EstimatedEmissions(it.Name, zone, false)
+					Expect(ok).To(BeTrue())
+					Expect(aws.Float64Value(metric.GetGauge().Value)).To(BeNumerically("~", gramsPerHour))
+				}
+			}
+		})
 	})
 	It("should launch instances in local zones", func() {
 		nodeClass.Status.Subnets = []v1beta1.Subnet{
@@ -958,6 +995,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Overhead.SystemReserved.Cpu().String()).To(Equal("0"))
 				Expect(it.Overhead.SystemReserved.Memory().String()).To(Equal("0"))
@@ -985,6 +1023,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Overhead.SystemReserved.Cpu().String()).To(Equal("2"))
 				Expect(it.Overhead.SystemReserved.Memory().String()).To(Equal("20Gi"))
@@ -1008,6 +1047,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Overhead.KubeReserved.Cpu().String()).To(Equal("80m"))
 				Expect(it.Overhead.KubeReserved.Memory().String()).To(Equal("893Mi"))
@@ -1040,6 +1080,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Overhead.KubeReserved.Cpu().String()).To(Equal("2"))
 				Expect(it.Overhead.KubeReserved.Memory().String()).To(Equal("10Gi"))
@@ -1079,6 +1120,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("500Mi"))
 				})
@@ -1108,6 +1150,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.1, 10))
 				})
@@ -1137,6 +1180,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("0"))
 				})
@@ -1166,6 +1210,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("50Mi"))
 				})
@@ -1197,6 +1242,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("500Mi"))
 				})
@@ -1229,6 +1275,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.1, 10))
 				})
@@ -1258,6 +1305,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("0"))
 				})
@@ -1291,6 +1339,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("1Gi"))
 				})
@@ -1311,6 +1360,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Overhead.EvictionThreshold.Cpu().String()).To(Equal("0"))
 				Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("100Mi"))
@@ -1345,6 +1395,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Overhead.EvictionThreshold.Memory().String()).To(Equal("3Gi"))
 			})
@@ -1377,6 +1428,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.05, 10))
 			})
@@ -1409,6 +1461,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Overhead.EvictionThreshold.Memory().Value()).To(BeNumerically("~", float64(it.Capacity.Memory().Value())*0.1, 10))
 			})
@@ -1432,6 +1485,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 35))
 				}
@@ -1450,6 +1504,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 394))
 				}
@@ -1476,6 +1531,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 10))
 			}
@@ -1501,6 +1557,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 10))
 			}
@@ -1530,6 +1587,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 				amiFamily,
 				nil,
+				carbon.Embodied{},
 			)
 			// t3.large
 			// maxInterfaces = 3
@@ -1564,6 +1622,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 				amiFamily,
 				nil,
+				carbon.Embodied{},
 			)
 			// t3.large
 			// maxInterfaces = 3
@@ -1595,6 +1654,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", lo.FromPtr(info.VCpuInfo.DefaultVCpus)))
 			}
@@ -1621,6 +1681,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", lo.Min([]int64{20, lo.FromPtr(info.VCpuInfo.DefaultVCpus) * 4})))
 			}
@@ -1647,6 +1708,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 					amiFamily,
 					nil,
+					carbon.Embodied{},
 				)
 				limitedPods := instancetype.ENILimitedPods(ctx, info)
 				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", limitedPods.Value()))
@@ -1674,6 +1736,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 35))
 				}
@@ -1692,6 +1755,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 						amiFamily,
 						nil,
+						carbon.Embodied{},
 					)
 					Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", 394))
 				}
@@ -2199,6 +2263,766 @@ var _ = Describe("InstanceTypeProvider", func() {
 			})
 		})
 	})
+	Context("Carbon Price", func() {
+		var instanceInfo map[string]*ec2.InstanceTypeInfo
+		offeringPrices := func(instanceTypes []*corecloudprovider.InstanceType) map[string]float64 {
+			prices := map[string]float64{}
+			for _, it := range instanceTypes {
+				for _, o := range it.Offerings {
+					prices[fmt.Sprintf("%s/%s/%s", it.Name, o.Requirements.Get(v1.LabelTopologyZone).Any(), o.Requirements.Get(corev1beta1.CapacityTypeLabelKey).Any())] = o.Price
+				}
+			}
+			return prices
+		}
+		BeforeEach(func() {
+			out, err := awsEnv.EC2API.DescribeInstanceTypesWithContext(ctx, &ec2.DescribeInstanceTypesInput{})
+			Expect(err).To(BeNil())
+			instanceInfo = lo.SliceToMap(out.InstanceTypes, func(info *ec2.InstanceTypeInfo) (string, *ec2.InstanceTypeInfo) {
+				return aws.StringValue(info.InstanceType), info
+			})
+		})
+		It("should not change offering prices without a carbon price", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices := offeringPrices(instanceTypes)
+
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](0)}
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(offeringPrices(instanceTypes)).To(Equal(prices))
+		})
+		It("should add the cost of estimated emissions to offering prices", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices := offeringPrices(instanceTypes)
+
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			for _, it := range instanceTypes {
+				power := instancetype.ComputePower(instanceInfo[it.Name])
+				for _, o := range it.Offerings {
+					zone := o.Requirements.Get(v1.LabelTopologyZone).Any()
+					intensity, ok := awsEnv.CarbonProvider.ZoneIntensity(zone)
+					Expect(ok).To(BeTrue())
+					gramsPerHour := power.Watts(instancetype.AverageUtilization) / 1000 * carbon.PUE(fake.DefaultRegion) * intensity
+					key := fmt.Sprintf("%s/%s/%s", it.Name, zone, o.Requirements.Get(corev1beta1.CapacityTypeLabelKey).Any())
+					Expect(o.Price).To(BeNumerically("~", prices[key]+100*gramsPerHour/1e6))
+				}
+			}
+		})
+		It("should not change offering prices when the carbon mode is Off", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices := offeringPrices(instanceTypes)
+
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeOff, CarbonPrice: lo.ToPtr[int64](100)}
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(offeringPrices(instanceTypes)).To(Equal(prices))
+		})
+		It("should add the cost of embodied emissions when includeEmbodied is set", func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100)}
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices := offeringPrices(instanceTypes)
+
+			nodeClass.Spec.CarbonPolicy.IncludeEmbodied = lo.ToPtr(true)
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			// m5.metal is the largest m5 instance type, so it's used as the host of m5.large
+			embodied := awsEnv.CarbonProvider.EmbodiedEmissions(instanceInfo["m5.large"], instanceInfo["m5.metal"])
+			Expect(embodied.GramsPerHour).To(BeNumerically(">", 0))
+			for _, it := range instanceTypes {
+				if it.Name != "m5.large" {
+					continue
+				}
+				for _, o := range it.Offerings {
+					key := fmt.Sprintf("%s/%s/%s", it.Name, o.Requirements.Get(v1.LabelTopologyZone).Any(), o.Requirements.Get(corev1beta1.CapacityTypeLabelKey).Any())
+					Expect(o.Price).To(BeNumerically("~", prices[key]+100*embodied.GramsPerHour/1e6))
+				}
+			}
+		})
+		It("should prefer lower emission instance types as the carbon price increases", func() {
+			// inf1.6xlarge is cheaper than g4dn.8xlarge but draws more power
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices := offeringPrices(instanceTypes)
+			Expect(prices["inf1.6xlarge/test-zone-1a/on-demand"]).To(BeNumerically("<", prices["g4dn.8xlarge/test-zone-1a/on-demand"]))
+
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](1_000_000)}
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices = offeringPrices(instanceTypes)
+			Expect(prices["g4dn.8xlarge/test-zone-1a/on-demand"]).To(BeNumerically("<", prices["inf1.6xlarge/test-zone-1a/on-demand"]))
+		})
+		It("should order offerings by the carbon intensity of their zone", func() {
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1a", 100)
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1b", 500)
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1c", 300)
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices := offeringPrices(instanceTypes)
+			// On-demand prices are the same in every zone of a region
+			Expect(prices["m5.large/test-zone-1a/on-demand"]).To(Equal(prices["m5.large/test-zone-1b/on-demand"]))
+			Expect(prices["m5.large/test-zone-1a/on-demand"]).To(Equal(prices["m5.large/test-zone-1c/on-demand"]))
+
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](1000)}
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices = offeringPrices(instanceTypes)
+			Expect(prices["m5.large/test-zone-1a/on-demand"]).To(BeNumerically("<", prices["m5.large/test-zone-1c/on-demand"]))
+			Expect(prices["m5.large/test-zone-1c/on-demand"]).To(BeNumerically("<", prices["m5.large/test-zone-1b/on-demand"]))
+		})
+		It("should reorder offerings when the carbon intensity of a zone changes", func() {
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1a", 100)
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1b", 500)
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](1000)}
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices := offeringPrices(instanceTypes)
+			Expect(prices["m5.large/test-zone-1a/on-demand"]).To(BeNumerically("<", prices["m5.large/test-zone-1b/on-demand"]))
+
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1a", 900)
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices = offeringPrices(instanceTypes)
+			Expect(prices["m5.large/test-zone-1b/on-demand"]).To(BeNumerically("<", prices["m5.large/test-zone-1a/on-demand"]))
+		})
+	})
+	Context("Carbon Ceiling", func() {
+		availableOfferings := func(instanceTypes []*corecloudprovider.InstanceType) sets.Set[string] {
+			available := sets.New[string]()
+			for _, it := range instanceTypes {
+				for _, o := range it.Offerings.Available() {
+					available.Insert(fmt.Sprintf("%s/%s/%s", it.Name, o.Requirements.Get(v1.LabelTopologyZone).Any(), o.Requirements.Get(corev1beta1.CapacityTypeLabelKey).Any()))
+				}
+			}
+			return available
+		}
+		It("should mark offerings unavailable when the zone is above the max intensity", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(availableOfferings(instanceTypes)).ToNot(BeEmpty())
+
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](1)}
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(availableOfferings(instanceTypes)).To(BeEmpty())
+		})
+		It("should not change availability when the zone is below the max intensity", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			available := availableOfferings(instanceTypes)
+
+			intensity, ok := awsEnv.CarbonProvider.ZoneIntensity("test-zone-1a")
+			Expect(ok).To(BeTrue())
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr(int64(intensity) + 1)}
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(availableOfferings(instanceTypes)).To(Equal(available))
+		})
+		It("should only mark offerings unavailable in zones above the max intensity", func() {
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1a", 100)
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1b", 500)
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1c", 100)
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](200)}
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			available := availableOfferings(instanceTypes)
+			Expect(available.Has("m5.large/test-zone-1a/on-demand")).To(BeTrue())
+			Expect(available.Has("m5.large/test-zone-1c/on-demand")).To(BeTrue())
+			Expect(available.UnsortedList()).ToNot(ContainElement(ContainSubstring("/test-zone-1b/")))
+		})
+		It("should schedule pods into zones below the max intensity", func() {
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1a", 500)
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1b", 100)
+			awsEnv.CarbonProvider.SetZoneIntensity("test-zone-1c", 500)
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](200)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(v1.LabelTopologyZone, "test-zone-1b"))
+		})
+		It("should ignore the max intensity when the carbon mode isn't Strict", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			available := availableOfferings(instanceTypes)
+
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, CarbonPrice: lo.ToPtr[int64](100), MaxIntensity: lo.ToPtr[int64](1)}
+			instanceTypes, err = awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(availableOfferings(instanceTypes)).To(Equal(available))
+		})
+		It("should not schedule pods when every zone is above the max intensity", func() {
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeStrict, MaxIntensity: lo.ToPtr[int64](1)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+	})
+	Context("Power", func() {
+		var instanceInfo map[string]*ec2.InstanceTypeInfo
+		BeforeEach(func() {
+			out, err := awsEnv.EC2API.DescribeInstanceTypesWithContext(ctx, &ec2.DescribeInstanceTypesInput{})
+			Expect(err).To(BeNil())
+			instanceInfo = lo.SliceToMap(out.InstanceTypes, func(info *ec2.InstanceTypeInfo) (string, *ec2.InstanceTypeInfo) {
+				return aws.StringValue(info.InstanceType), info
+			})
+		})
+		It("should interpolate between idle and max watts", func() {
+			power := instancetype.ComputePower(instanceInfo["m5.large"])
+			Expect(power.IdleWatts).To(BeNumerically(">", 0))
+			Expect(power.MaxWatts).To(BeNumerically(">", power.IdleWatts))
+			Expect(power.Watts(0)).To(BeNumerically("==", power.IdleWatts))
+			Expect(power.Watts(1)).To(BeNumerically("==", power.MaxWatts))
+			Expect(power.Watts(2)).To(BeNumerically("==", power.MaxWatts))
+			Expect(power.Watts(instancetype.AverageUtilization)).To(BeNumerically("~", (power.IdleWatts+power.MaxWatts)/2))
+		})
+		It("should use the coefficients of the instance family's processor", func() {
+			info := instanceInfo["c6g.large"]
+			power := instancetype.ComputePower(info)
+			vcpus := float64(aws.Int64Value(info.VCpuInfo.DefaultVCpus))
+			memoryWatts := float64(aws.Int64Value(info.MemoryInfo.SizeInMiB)) / 1024 * 0.392
+			Expect(power.MaxWatts).To(BeNumerically("~", vcpus*instancetype.MicroarchitectureCPUWatts["Graviton2"].MaxWatts+memoryWatts))
+			Expect(power.IdleWatts).To(BeNumerically("~", vcpus*instancetype.MicroarchitectureCPUWatts["Graviton2"].IdleWatts+memoryWatts))
+		})
+		It("should estimate lower draw for Graviton than Intel at the same size", func() {
+			graviton := instancetype.ComputePower(instanceInfo["t4g.xlarge"])
+			intel := instancetype.ComputePower(instanceInfo["m5.xlarge"])
+			Expect(graviton.MaxWatts).To(BeNumerically("<", intel.MaxWatts))
+		})
+		It("should include the draw of GPUs", func() {
+			info := instanceInfo["p3.8xlarge"]
+			gpus := float64(aws.Int64Value(info.GpuInfo.Gpus[0].Count))
+			power := instancetype.ComputePower(info)
+			Expect(power.MaxWatts).To(BeNumerically(">", gpus*instancetype.AcceleratorWatts["v100"].MaxWatts))
+		})
+		It("should include the draw of inference accelerators", func() {
+			info := instanceInfo["inf1.2xlarge"]
+			withoutAccelerators := &ec2.InstanceTypeInfo{}
+			Expect(mergo.Merge(withoutAccelerators, info)).To(Succeed())
+			withoutAccelerators.InferenceAcceleratorInfo = nil
+			Expect(instancetype.ComputePower(info).MaxWatts - instancetype.ComputePower(withoutAccelerators).MaxWatts).
+				To(BeNumerically("~", instancetype.AcceleratorWatts["inferentia"].MaxWatts))
+		})
+		It("should fall back to a coefficient based on architecture for unknown families", func() {
+			info := &ec2.InstanceTypeInfo{}
+			Expect(mergo.Merge(info, instanceInfo["c6g.large"])).To(Succeed())
+			info.InstanceType = aws.String("zz9g.large")
+			Expect(instancetype.ComputePower(info)).To(Equal(instancetype.ComputePower(instanceInfo["c6g.large"])))
+		})
+		It("should fall back to the average coefficient for unknown families and manufacturers", func() {
+			info := &ec2.InstanceTypeInfo{}
+			Expect(mergo.Merge(info, instanceInfo["m5.large"])).To(Succeed())
+			info.InstanceType = aws.String("zz9.large")
+			info.ProcessorInfo = &ec2.ProcessorInfo{SupportedArchitectures: aws.StringSlice([]string{"x86_64"})}
+			vcpus := float64(aws.Int64Value(info.VCpuInfo.DefaultVCpus))
+			memoryWatts := float64(aws.Int64Value(info.MemoryInfo.SizeInMiB)) / 1024 * 0.392
+			Expect(instancetype.ComputePower(info).MaxWatts).To(BeNumerically("~", vcpus*instancetype.MicroarchitectureCPUWatts["Average"].MaxWatts+memoryWatts))
+		})
+		It("should compute the same draw from the labels of an instance type", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			Expect(instanceTypes).ToNot(BeEmpty())
+			for _, it := range instanceTypes {
+				labels := map[string]string{}
+				for key, requirement := range it.Requirements {
+					if requirement.Operator() == v1.NodeSelectorOpIn && requirement.Len() == 1 {
+						labels[key] = requirement.Any()
+					}
+				}
+				info, ok := instancetype.InstanceTypeInfoFromLabels(labels)
+				Expect(ok).To(BeTrue())
+				Expect(instancetype.ComputePower(info)).To(Equal(instancetype.ComputePower(instanceInfo[it.Name])), it.Name)
+			}
+		})
+		It("should not compute the draw from labels that don't describe the instance type", func() {
+			_, ok := instancetype.InstanceTypeInfoFromLabels(map[string]string{v1.LabelInstanceTypeStable: "m5.large"})
+			Expect(ok).To(BeFalse())
+		})
+	})
+	Context("Efficiency", func() {
+		instanceType := func(name, generation, manufacturer string) *corecloudprovider.InstanceType {
+			requirements := scheduling.NewRequirements(
+				scheduling.NewRequirement(v1.LabelInstanceTypeStable, v1.NodeSelectorOpIn, name),
+				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
+				scheduling.NewRequirement(v1beta1.LabelInstanceCPU, v1.NodeSelectorOpIn, "2"),
+				scheduling.NewRequirement(v1beta1.LabelInstanceMemory, v1.NodeSelectorOpIn, "8192"),
+				scheduling.NewRequirement(v1beta1.LabelInstanceGeneration, v1.NodeSelectorOpIn, generation),
+			)
+			if manufacturer != "" {
+				requirements.Add(scheduling.NewRequirement(v1beta1.LabelInstanceCPUManufacturer, v1.NodeSelectorOpIn, manufacturer))
+			}
+			return &corecloudprovider.InstanceType{Name: name, Requirements: requirements}
+		}
+		It("should score newer generations higher when the draw is the same", func() {
+			Expect(instancetype.EfficiencyScore(instanceType("zz6.large", "6", ""))).To(BeNumerically(">", instancetype.EfficiencyScore(instanceType("zz5.large", "5", ""))))
+		})
+		It("should score Graviton higher than Intel at the same size", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			byName := lo.SliceToMap(instanceTypes, func(it *corecloudprovider.InstanceType) (string, *corecloudprovider.InstanceType) { return it.Name, it })
+			Expect(instancetype.EfficiencyScore(byName["t4g.xlarge"])).To(BeNumerically(">", instancetype.EfficiencyScore(byName["m5.xlarge"])))
+		})
+		It("should score instance types with accelerators lower than those without", func() {
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			byName := lo.SliceToMap(instanceTypes, func(it *corecloudprovider.InstanceType) (string, *corecloudprovider.InstanceType) { return it.Name, it })
+			Expect(instancetype.EfficiencyScore(byName["p3.8xlarge"])).To(BeNumerically("<", instancetype.EfficiencyScore(byName["m5.xlarge"])))
+		})
+		It("should score instance types that don't describe their CPU and memory as 0", func() {
+			it := &corecloudprovider.InstanceType{Name: "zz5.large", Requirements: scheduling.NewRequirements(
+				scheduling.NewRequirement(v1.LabelInstanceTypeStable, v1.NodeSelectorOpIn, "zz5.large"),
+			)}
+			Expect(instancetype.EfficiencyScore(it)).To(BeZero())
+		})
+		It("should order instance types from the most to the least efficient", func() {
+			older, newer, graviton := instanceType("zz5.large", "5", ""), instanceType("zz6.large", "6", ""), instanceType("zz6g.large", "6", "aws")
+			input := []*corecloudprovider.InstanceType{older, graviton, newer}
+			Expect(instancetype.OrderByEfficiency(input)).To(Equal([]*corecloudprovider.InstanceType{graviton, newer, older}))
+			Expect(input).To(Equal([]*corecloudprovider.InstanceType{older, graviton, newer}))
+		})
+		It("should launch the most efficient instance types when truncating", func() {
+			instances := fake.MakeInstances()
+			awsEnv.EC2API.DescribeInstanceTypesOutput.Set(&ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: fake.MakeInstances(),
+			})
+			awsEnv.EC2API.DescribeInstanceTypeOfferingsOutput.Set(&ec2.DescribeInstanceTypeOfferingsOutput{
+				InstanceTypeOfferings: fake.MakeInstanceOfferings(instances),
+			})
+			Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypes(ctx)).To(Succeed())
+			Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypeOfferings(ctx)).To(Succeed())
+			nodeClass.Spec.CarbonPolicy = &v1beta1.CarbonPolicy{Mode: v1beta1.CarbonModeWeighted, PreferEfficientInstanceTypes: lo.ToPtr(true)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{
+				ResourceRequirements: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+					Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+				},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			its, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			// Metal instance types are exotic, so they're filtered out before truncating
+			reqs := scheduling.NewNodeSelectorRequirementsWithMinValues(nodePool.Spec.Template.Spec.Requirements...)
+			its = lo.Reject(corecloudprovider.InstanceTypes(its).Compatible(reqs), func(it *corecloudprovider.InstanceType, _ int) bool {
+				return it.Requirements.Get(v1beta1.LabelInstanceSize).Has("metal")
+			})
+			expected := sets.NewString(lo.Map(instancetype.OrderByEfficiency(its)[:60], func(i *corecloudprovider.InstanceType, _ int) string {
+				return i.Name
+			})...)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(call.LaunchTemplateConfigs).To(HaveLen(1))
+
+			Expect(call.LaunchTemplateConfigs[0].Overrides).To(HaveLen(60))
+			for _, override := range call.LaunchTemplateConfigs[0].Overrides {
+				Expect(expected.Has(aws.StringValue(override.InstanceType))).To(BeTrue(), fmt.Sprintf("expected %s to exist in set", aws.StringValue(override.InstanceType)))
+			}
+		})
+	})
+	Context("Operating System Pricing", func() {
+		onDemandPrices := func(nodeClass *v1beta1.EC2NodeClass) map[string]float64 {
+			GinkgoHelper()
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			prices := map[string]float64{}
+			for _, it := range instanceTypes {
+				for _, o := range it.Offerings {
+					if o.Requirements.Get(corev1beta1.CapacityTypeLabelKey).Any() == corev1beta1.CapacityTypeOnDemand {
+						prices[it.Name] = o.Price
+					}
+				}
+			}
+			return prices
+		}
+		BeforeEach(func() {
+			awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+				PriceList: []aws.JSONValue{
+					fake.NewOnDemandPriceWithOperatingSystem("m5.large", 0.096, "Linux"),
+					fake.NewOnDemandPriceWithOperatingSystem("m5.large", 0.188, "Windows"),
+					fake.NewOnDemandPriceWithOperatingSystem("m5.xlarge", 0.192, "Linux"),
+				},
+			})
+			Expect(awsEnv.PricingProvider.UpdateOnDemandPricing(ctx)).To(Succeed())
+		})
+		It("should price the offerings of windows AMI families from the windows price list", func() {
+			Expect(onDemandPrices(windowsNodeClass)).To(HaveKeyWithValue("m5.large", BeNumerically("==", 0.188)))
+		})
+		It("should price the offerings of linux AMI families from the linux price list", func() {
+			Expect(onDemandPrices(nodeClass)).To(HaveKeyWithValue("m5.large", BeNumerically("==", 0.096)))
+		})
+		It("should fall back to the linux price for windows offerings without a windows price", func() {
+			Expect(onDemandPrices(windowsNodeClass)).To(HaveKeyWithValue("m5.xlarge", BeNumerically("==", 0.192)))
+		})
+		Context("Spot", func() {
+			spotPrices := func(nodeClass *v1beta1.EC2NodeClass) map[string]float64 {
+				GinkgoHelper()
+				instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+				Expect(err).To(BeNil())
+				prices := map[string]float64{}
+				for _, it := range instanceTypes {
+					for _, o := range it.Offerings {
+						if o.Requirements.Get(corev1beta1.CapacityTypeLabelKey).Any() == corev1beta1.CapacityTypeSpot &&
+							o.Requirements.Get(v1.LabelTopologyZone).Any() == "test-zone-1a" {
+							prices[it.Name] = o.Price
+						}
+					}
+				}
+				return prices
+			}
+			BeforeEach(func() {
+				now := time.Now()
+				awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
+					SpotPriceHistory: []*ec2.SpotPrice{
+						{
+							AvailabilityZone:   aws.String("test-zone-1a"),
+							InstanceType:       aws.String("m5.large"),
+							ProductDescription: aws.String("Linux/UNIX"),
+							SpotPrice:          aws.String("0.04"),
+							Timestamp:          &now,
+						},
+						{
+							AvailabilityZone:   aws.String("test-zone-1a"),
+							InstanceType:       aws.String("m5.large"),
+							ProductDescription: aws.String("Windows"),
+							SpotPrice:          aws.String("0.13"),
+							Timestamp:          &now,
+						},
+						{
+							AvailabilityZone:   aws.String("test-zone-1a"),
+							InstanceType:       aws.String("m5.xlarge"),
+							ProductDescription: aws.String("Linux/UNIX"),
+							SpotPrice:          aws.String("0.08"),
+							Timestamp:          &now,
+						},
+					},
+				})
+				Expect(awsEnv.PricingProvider.UpdateSpotPricing(ctx)).To(Succeed())
+			})
+			It("should price the spot offerings of windows AMI families from the windows spot price history", func() {
+				Expect(spotPrices(windowsNodeClass)).To(HaveKeyWithValue("m5.large", BeNumerically("==", 0.13)))
+			})
+			It("should price the spot offerings of linux AMI families from the linux spot price history", func() {
+				Expect(spotPrices(nodeClass)).To(HaveKeyWithValue("m5.large", BeNumerically("==", 0.04)))
+			})
+			It("should fall back to the linux spot price for windows offerings without a windows spot price", func() {
+				Expect(spotPrices(windowsNodeClass)).To(HaveKeyWithValue("m5.xlarge", BeNumerically("==", 0.08)))
+			})
+		})
+		Context("Pricing Commitments", func() {
+			setCommitments := func(commitments string) {
+				GinkgoHelper()
+				file := filepath.Join(GinkgoT().TempDir(), "commitments.yaml")
+				Expect(os.WriteFile(file, []byte(commitments), 0o600)).To(Succeed())
+				ctx = options.ToContext(ctx, test.Options(test.OptionsFields{PricingCommitmentsFile: lo.ToPtr(file)}))
+			}
+			nodeClaim := func(operatingSystem string) *corev1beta1.NodeClaim {
+				return coretest.NodeClaim(corev1beta1.NodeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							corev1beta1.CapacityTypeLabelKey: corev1beta1.CapacityTypeOnDemand,
+							v1.LabelInstanceTypeStable:       "m5.large",
+							v1.LabelOSStable:                 operatingSystem,
+							v1.LabelTopologyRegion:           fake.DefaultRegion,
+							v1.LabelTopologyZone:             "test-zone-1a",
+						},
+					},
+				})
+			}
+			It("should not apply linux commitments to the offerings of windows AMI families", func() {
+				setCommitments(`
+commitments:
+- name: reserved-instance
+  instanceFamily: m5
+  count: 1
+  effectivePrice: 0.05
+`)
+				Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+				Expect(onDemandPrices(windowsNodeClass)).To(HaveKeyWithValue("m5.large", BeNumerically("==", 0.188)))
+				Expect(onDemandPrices(nodeClass)).To(HaveKeyWithValue("m5.large", BeNumerically("==", 0.05)))
+			})
+			It("should apply windows commitments to the offerings of windows AMI families only", func() {
+				setCommitments(`
+commitments:
+- name: reserved-instance
+  instanceFamily: m5
+  operatingSystem: windows
+  count: 1
+  effectivePrice: 0.1
+`)
+				Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+				Expect(onDemandPrices(windowsNodeClass)).To(HaveKeyWithValue("m5.large", BeNumerically("==", 0.1)))
+				Expect(onDemandPrices(nodeClass)).To(HaveKeyWithValue("m5.large", BeNumerically("==", 0.096)))
+			})
+			It("should only count nodeclaims of the commitment's operating system against it", func() {
+				setCommitments(`
+commitments:
+- name: reserved-instance
+  instanceFamily: m5
+  count: 1
+  effectivePrice: 0.05
+`)
+				Expect(awsEnv.CommitmentProvider.Update(ctx, []*corev1beta1.NodeClaim{
+					nodeClaim(string(v1.Windows)),
+					nodeClaim(string(v1.Windows)),
+				})).To(Succeed())
+				Expect(awsEnv.CommitmentProvider.Usage()[0].Used).To(Equal(0))
+				Expect(onDemandPrices(nodeClass)).To(HaveKeyWithValue("m5.large", BeNumerically("==", 0.05)))
+
+				Expect(awsEnv.CommitmentProvider.Update(ctx, []*corev1beta1.NodeClaim{
+					nodeClaim(string(v1.Linux)),
+				})).To(Succeed())
+				Expect(awsEnv.CommitmentProvider.Usage()[0].Used).To(Equal(1))
+			})
+		})
+	})
+	Context("Pricing Commitments", func() {
+		setCommitments := func(commitments string) {
+			GinkgoHelper()
+			file := filepath.Join(GinkgoT().TempDir(), "commitments.yaml")
+			Expect(os.WriteFile(file, []byte(commitments), 0o600)).To(Succeed())
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{PricingCommitmentsFile: lo.ToPtr(file)}))
+		}
+		nodeClaim := func(instanceType, capacityType, zone string) *corev1beta1.NodeClaim {
+			return coretest.NodeClaim(corev1beta1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						corev1beta1.CapacityTypeLabelKey: capacityType,
+						v1.LabelInstanceTypeStable:       instanceType,
+						v1.LabelTopologyRegion:           fake.DefaultRegion,
+						v1.LabelTopologyZone:             zone,
+					},
+				},
+			})
+		}
+		offeringPrice := func(instanceType, capacityType, zone string) float64 {
+			GinkgoHelper()
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			it, ok := lo.Find(instanceTypes, func(it *corecloudprovider.InstanceType) bool { return it.Name == instanceType })
+			Expect(ok).To(BeTrue())
+			offering, ok := it.Offerings.Get(scheduling.NewRequirements(
+				scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, capacityType),
+				scheduling.NewRequirement(v1.LabelTopologyZone, v1.NodeSelectorOpIn, zone),
+			))
+			Expect(ok).To(BeTrue())
+			return offering.Price
+		}
+		var listPrice float64
+		BeforeEach(func() {
+			var ok bool
+			listPrice, ok = awsEnv.PricingProvider.OnDemandPrice("m5.large")
+			Expect(ok).To(BeTrue())
+		})
+		It("should not change offering prices without a commitments file", func() {
+			Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("==", listPrice))
+		})
+		It("should lower the on-demand price of covered offerings by the effective rate", func() {
+			setCommitments(`
+commitments:
+- name: compute-savings-plan
+  instanceFamily: m5
+  sizes: [large]
+  count: 2
+  effectiveRate: 0.6
+`)
+			Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+			for _, zone := range []string{"test-zone-1a", "test-zone-1b", "test-zone-1c"} {
+				Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, zone)).To(BeNumerically("~", listPrice*0.6))
+			}
+			xlargePrice, ok := awsEnv.PricingProvider.OnDemandPrice("m5.xlarge")
+			Expect(ok).To(BeTrue())
+			Expect(offeringPrice("m5.xlarge", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("==", xlargePrice))
+			spotPrice, ok := awsEnv.PricingProvider.SpotPrice("m5.large", "test-zone-1a")
+			Expect(ok).To(BeTrue())
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeSpot, "test-zone-1a")).To(BeNumerically("==", spotPrice))
+		})
+		It("should only lower the price of offerings in the zone of a zonal commitment", func() {
+			setCommitments(`
+commitments:
+- name: zonal-reserved-instance
+  instanceFamily: m5
+  zone: test-zone-1a
+  count: 1
+  effectivePrice: 0.01
+`)
+			Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("==", 0.01))
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1b")).To(BeNumerically("==", listPrice))
+		})
+		It("should use the lowest effective price of the covering commitments", func() {
+			setCommitments(`
+commitments:
+- name: compute-savings-plan
+  instanceFamily: m5
+  count: 1
+  effectiveRate: 0.7
+- name: reserved-instance
+  instanceFamily: m5
+  sizes: [large]
+  count: 1
+  effectiveRate: 0.5
+`)
+			Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", listPrice*0.5))
+		})
+		It("should keep the effective price while the commitment covers every nodeclaim", func() {
+			setCommitments(`
+commitments:
+- name: reserved-instance
+  instanceFamily: m5
+  count: 2
+  effectiveRate: 0.5
+`)
+			Expect(awsEnv.CommitmentProvider.Update(ctx, []*corev1beta1.NodeClaim{
+				nodeClaim("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a"),
+				nodeClaim("m5.xlarge", corev1beta1.CapacityTypeOnDemand, "test-zone-1b"),
+			})).To(Succeed())
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", listPrice*0.5))
+		})
+		It("should restore the on-demand price once the commitment is used up", func() {
+			setCommitments(`
+commitments:
+- name: reserved-instance
+  instanceFamily: m5
+  count: 1
+  effectiveRate: 0.5
+`)
+			Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", listPrice*0.5))
+
+			Expect(awsEnv.CommitmentProvider.Update(ctx, []*corev1beta1.NodeClaim{
+				nodeClaim("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a"),
+				nodeClaim("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1b"),
+			})).To(Succeed())
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("==", listPrice))
+		})
+		It("should not count spot nodeclaims or uncovered instance types against the commitment", func() {
+			setCommitments(`
+commitments:
+- name: reserved-instance
+  instanceFamily: m5
+  count: 1
+  effectiveRate: 0.5
+`)
+			Expect(awsEnv.CommitmentProvider.Update(ctx, []*corev1beta1.NodeClaim{
+				nodeClaim("m5.large", corev1beta1.CapacityTypeSpot, "test-zone-1a"),
+				nodeClaim("m5.large", corev1beta1.CapacityTypeSpot, "test-zone-1b"),
+				nodeClaim("c5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a"),
+				nodeClaim("c5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1b"),
+			})).To(Succeed())
+			Expect(awsEnv.CommitmentProvider.Usage()).To(HaveLen(1))
+			Expect(awsEnv.CommitmentProvider.Usage()[0].Used).To(Equal(0))
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", listPrice*0.5))
+		})
+		It("should reprice offerings when the commitments file changes", func() {
+			setCommitments(`
+commitments:
+- name: reserved-instance
+  instanceFamily: m5
+  count: 1
+  effectiveRate: 0.5
+`)
+			Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", listPrice*0.5))
+
+			setCommitments(`
+commitments:
+- name: reserved-instance
+  instanceFamily: m5
+  count: 1
+  effectiveRate: 0.8
+`)
+			Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", listPrice*0.8))
+		})
+		DescribeTable("should fail to update with an invalid commitments file",
+			func(commitments string) {
+				setCommitments(commitments)
+				Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).ToNot(Succeed())
+			},
+			Entry("missing instance family", "commitments:\n- name: ri\n  count: 1\n  effectiveRate: 0.5\n"),
+			Entry("non-positive count", "commitments:\n- name: ri\n  instanceFamily: m5\n  count: 0\n  effectiveRate: 0.5\n"),
+			Entry("both effective price and rate", "commitments:\n- name: ri\n  instanceFamily: m5\n  count: 1\n  effectiveRate: 0.5\n  effectivePrice: 0.05\n"),
+			Entry("effective rate above 1", "commitments:\n- name: ri\n  instanceFamily: m5\n  count: 1\n  effectiveRate: 1.5\n"),
+			Entry("unknown operating system", "commitments:\n- name: ri\n  instanceFamily: m5\n  operatingSystem: macos\n  count: 1\n  effectiveRate: 0.5\n"),
+			Entry("duplicate names", "commitments:\n- name: ri\n  instanceFamily: m5\n  count: 1\n  effectiveRate: 0.5\n- name: ri\n  instanceFamily: c5\n  count: 1\n  effectiveRate: 0.5\n"),
+			Entry("unknown field", "commitments:\n- name: ri\n  instanceFamily: m5\n  count: 1\n  effectiveRate: 0.5\n  term: 1y\n"),
+		)
+	})
+	Context("Pricing Overrides", func() {
+		offeringPrice := func(instanceType, capacityType, zone string) float64 {
+			GinkgoHelper()
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+			Expect(err).To(BeNil())
+			it, ok := lo.Find(instanceTypes, func(it *corecloudprovider.InstanceType) bool { return it.Name == instanceType })
+			Expect(ok).To(BeTrue())
+			offering, ok := it.Offerings.Get(scheduling.NewRequirements(
+				scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, capacityType),
+				scheduling.NewRequirement(v1.LabelTopologyZone, v1.NodeSelectorOpIn, zone),
+			))
+			Expect(ok).To(BeTrue())
+			return offering.Price
+		}
+		var onDemandPrice, spotPrice float64
+		BeforeEach(func() {
+			var ok bool
+			onDemandPrice, ok = awsEnv.PricingProvider.OnDemandPrice("m5.large")
+			Expect(ok).To(BeTrue())
+			spotPrice, ok = awsEnv.PricingProvider.SpotPrice("m5.large", "test-zone-1a")
+			Expect(ok).To(BeTrue())
+		})
+		It("should scale offering prices by a multiplier", func() {
+			awsEnv.PricingOverrides.Set(ctx, []pricing.Override{{Multiplier: lo.ToPtr(0.9)}})
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", onDemandPrice*0.9))
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeSpot, "test-zone-1a")).To(BeNumerically("~", spotPrice*0.9))
+		})
+		It("should only override the offerings that an override selects", func() {
+			awsEnv.PricingOverrides.Set(ctx, []pricing.Override{
+				{InstanceType: "m5.large", CapacityType: corev1beta1.CapacityTypeSpot, Zone: "test-zone-1a", Price: lo.ToPtr(0.01)},
+			})
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeSpot, "test-zone-1a")).To(BeNumerically("==", 0.01))
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeSpot, "test-zone-1b")).To(BeNumerically("==", spotPrice))
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("==", onDemandPrice))
+		})
+		It("should prefer the most specific override", func() {
+			xlargePrice, ok := awsEnv.PricingProvider.OnDemandPrice("m5.xlarge")
+			Expect(ok).To(BeTrue())
+			awsEnv.PricingOverrides.Set(ctx, []pricing.Override{
+				{Multiplier: lo.ToPtr(0.9)},
+				{CapacityType: corev1beta1.CapacityTypeOnDemand, Multiplier: lo.ToPtr(0.8)},
+				{InstanceType: "m5.large", Multiplier: lo.ToPtr(0.5)},
+			})
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", onDemandPrice*0.5))
+			Expect(offeringPrice("m5.xlarge", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", xlargePrice*0.8))
+		})
+		It("should reprice offerings when the overrides change", func() {
+			awsEnv.PricingOverrides.Set(ctx, []pricing.Override{{InstanceType: "m5.large", Multiplier: lo.ToPtr(0.9)}})
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", onDemandPrice*0.9))
+			awsEnv.PricingOverrides.Set(ctx, nil)
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("==", onDemandPrice))
+		})
+		It("should apply commitments to the overridden on-demand price", func() {
+			file := filepath.Join(GinkgoT().TempDir(), "commitments.yaml")
+			Expect(os.WriteFile(file, []byte(`
+commitments:
+- name: compute-savings-plan
+  instanceFamily: m5
+  count: 1
+  effectiveRate: 0.5
+`), 0o600)).To(Succeed())
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{PricingCommitmentsFile: lo.ToPtr(file)}))
+			Expect(awsEnv.CommitmentProvider.Update(ctx, nil)).To(Succeed())
+			awsEnv.PricingOverrides.Set(ctx, []pricing.Override{{Multiplier: lo.ToPtr(0.9)}})
+			Expect(offeringPrice("m5.large", corev1beta1.CapacityTypeOnDemand, "test-zone-1a")).To(BeNumerically("~", onDemandPrice*0.9*0.5))
+		})
+	})
 	Context("Provider Cache", func() {
 		// Keeping the Cache testing in one IT block to validate the combinatorial expansion of instance types generated by different configs
 		It("changes to kubelet configuration fields should result in a different set of instances types", func() {
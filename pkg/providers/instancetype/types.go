@@ -33,6 +33,7 @@ import (
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
@@ -51,11 +52,11 @@ var (
 func NewInstanceType(ctx context.Context, info *ec2.InstanceTypeInfo, region string,
 	blockDeviceMappings []*v1beta1.BlockDeviceMapping, instanceStorePolicy *v1beta1.InstanceStorePolicy, maxPods *int32, podsPerCore *int32,
 	kubeReserved map[string]string, systemReserved map[string]string, evictionHard map[string]string, evictionSoft map[string]string,
-	amiFamily amifamily.AMIFamily, offerings cloudprovider.Offerings) *cloudprovider.InstanceType {
+	amiFamily amifamily.AMIFamily, offerings cloudprovider.Offerings, embodied carbon.Embodied) *cloudprovider.InstanceType {
 
 	it := &cloudprovider.InstanceType{
 		Name:         aws.StringValue(info.InstanceType),
-		Requirements: computeRequirements(info, offerings, region, amiFamily),
+		Requirements: computeRequirements(info, offerings, region, amiFamily, embodied),
 		Offerings:    offerings,
 		Capacity:     computeCapacity(ctx, info, amiFamily, blockDeviceMappings, instanceStorePolicy, maxPods, podsPerCore),
 		Overhead: &cloudprovider.InstanceTypeOverhead{
@@ -71,7 +72,8 @@ func NewInstanceType(ctx context.Context, info *ec2.InstanceTypeInfo, region str
 }
 
 //nolint:gocyclo
-func computeRequirements(info *ec2.InstanceTypeInfo, offerings cloudprovider.Offerings, region string, amiFamily amifamily.AMIFamily) scheduling.Requirements {
+func computeRequirements(info *ec2.InstanceTypeInfo, offerings cloudprovider.Offerings, region string, amiFamily amifamily.AMIFamily,
+	embodied carbon.Embodied) scheduling.Requirements {
 	requirements := scheduling.NewRequirements(
 		// Well Known Upstream
 		scheduling.NewRequirement(v1.LabelInstanceTypeStable, v1.NodeSelectorOpIn, aws.StringValue(info.InstanceType)),
@@ -106,6 +108,8 @@ func computeRequirements(info *ec2.InstanceTypeInfo, offerings cloudprovider.Off
 		scheduling.NewRequirement(v1beta1.LabelInstanceAcceleratorCount, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceHypervisor, v1.NodeSelectorOpIn, aws.StringValue(info.Hypervisor)),
 		scheduling.NewRequirement(v1beta1.LabelInstanceEncryptionInTransitSupported, v1.NodeSelectorOpIn, fmt.Sprint(aws.BoolValue(info.NetworkInfo.EncryptionInTransitSupported))),
+		scheduling.NewRequirement(v1beta1.LabelInstanceWattsMax, v1.NodeSelectorOpIn, fmt.Sprint(int64(math.Round(ComputePower(info).MaxWatts)))),
+		scheduling.NewRequirement(v1beta1.LabelInstanceEmbodiedKgCO2e, v1.NodeSelectorOpIn, fmt.Sprint(int64(math.Round(embodied.KgCO2e)))),
 	)
 	// Only add zone-id label when available in offerings. It may not be available if a user has upgraded from a
 	// previous version of Karpenter w/o zone-id support and the nodeclass subnet status has not yet updated.
@@ -115,6 +119,13 @@ func computeRequirements(info *ec2.InstanceTypeInfo, offerings cloudprovider.Off
 	}); len(zoneIDs) != 0 {
 		requirements.Add(scheduling.NewRequirement(v1beta1.LabelTopologyZoneID, v1.NodeSelectorOpIn, zoneIDs...))
 	}
+	// Only add the carbon tier label when the intensity of at least one available zone is known
+	if tiers := lo.FilterMap(offerings.Available(), func(o cloudprovider.Offering, _ int) (string, bool) {
+		tier := o.Requirements.Get(v1beta1.LabelTopologyZoneCarbonTier).Any()
+		return tier, tier != ""
+	}); len(tiers) != 0 {
+		requirements.Add(scheduling.NewRequirement(v1beta1.LabelTopologyZoneCarbonTier, v1.NodeSelectorOpIn, tiers...))
+	}
 	// Instance Type Labels
 	instanceFamilyParts := instanceTypeScheme.FindStringSubmatch(aws.StringValue(info.InstanceType))
 	if len(instanceFamilyParts) == 4 {
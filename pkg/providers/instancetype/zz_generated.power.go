@@ -0,0 +1,169 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetype
+
+// GENERATED FILE. DO NOT EDIT DIRECTLY.
+// Update the CSV files in hack/code/power_gen and re-generate to edit
+
+var (
+	// MicroarchitectureCPUWatts is the idle and max draw of a single vCPU for each CPU microarchitecture
+	MicroarchitectureCPUWatts = map[string]Power{
+		"AMD EPYC 1st Gen": {IdleWatts: 0.82, MaxWatts: 2.55},
+		"AMD EPYC 2nd Gen": {IdleWatts: 0.47, MaxWatts: 1.69},
+		"AMD EPYC 3rd Gen": {IdleWatts: 0.45, MaxWatts: 2.02},
+		"AMD EPYC 4th Gen": {IdleWatts: 0.45, MaxWatts: 2.02},
+		"Average":          {IdleWatts: 0.74, MaxWatts: 3.5},
+		"Broadwell":        {IdleWatts: 0.71, MaxWatts: 3.69},
+		"Cascade Lake":     {IdleWatts: 0.64, MaxWatts: 3.97},
+		"Graviton":         {IdleWatts: 0.47, MaxWatts: 1.69},
+		"Graviton2":        {IdleWatts: 0.47, MaxWatts: 1.69},
+		"Graviton3":        {IdleWatts: 0.47, MaxWatts: 1.69},
+		"Graviton4":        {IdleWatts: 0.47, MaxWatts: 1.69},
+		"Haswell":          {IdleWatts: 1.9, MaxWatts: 6.01},
+		"Ice Lake":         {IdleWatts: 0.64, MaxWatts: 3.97},
+		"Sapphire Rapids":  {IdleWatts: 0.64, MaxWatts: 3.97},
+		"Skylake":          {IdleWatts: 0.65, MaxWatts: 4.26},
+	}
+	// InstanceFamilyCPUWatts is the idle and max draw of a single vCPU for each instance family
+	InstanceFamilyCPUWatts = map[string]Power{
+		"a1":       {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton
+		"c4":       {IdleWatts: 1.9, MaxWatts: 6.01},  // Haswell
+		"c5":       {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"c5a":      {IdleWatts: 0.47, MaxWatts: 1.69}, // AMD EPYC 2nd Gen
+		"c5ad":     {IdleWatts: 0.47, MaxWatts: 1.69}, // AMD EPYC 2nd Gen
+		"c5d":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"c5n":      {IdleWatts: 0.65, MaxWatts: 4.26}, // Skylake
+		"c6a":      {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 3rd Gen
+		"c6g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"c6gd":     {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"c6gn":     {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"c6i":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"c6id":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"c6in":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"c7a":      {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 4th Gen
+		"c7g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton3
+		"c7gd":     {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton3
+		"c7gn":     {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton3
+		"c7i":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Sapphire Rapids
+		"c8g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton4
+		"d2":       {IdleWatts: 1.9, MaxWatts: 6.01},  // Haswell
+		"d3":       {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"d3en":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"dl1":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"f1":       {IdleWatts: 0.71, MaxWatts: 3.69}, // Broadwell
+		"g3":       {IdleWatts: 0.71, MaxWatts: 3.69}, // Broadwell
+		"g4ad":     {IdleWatts: 0.47, MaxWatts: 1.69}, // AMD EPYC 2nd Gen
+		"g4dn":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"g5":       {IdleWatts: 0.47, MaxWatts: 1.69}, // AMD EPYC 2nd Gen
+		"g5g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"g6":       {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 3rd Gen
+		"h1":       {IdleWatts: 0.71, MaxWatts: 3.69}, // Broadwell
+		"hpc6a":    {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 3rd Gen
+		"hpc7a":    {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 4th Gen
+		"hpc7g":    {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton3
+		"i3":       {IdleWatts: 0.71, MaxWatts: 3.69}, // Broadwell
+		"i3en":     {IdleWatts: 0.65, MaxWatts: 4.26}, // Skylake
+		"i4g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"i4i":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"im4gn":    {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"inf1":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"inf2":     {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 3rd Gen
+		"is4gen":   {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"m4":       {IdleWatts: 0.71, MaxWatts: 3.69}, // Broadwell
+		"m5":       {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"m5a":      {IdleWatts: 0.82, MaxWatts: 2.55}, // AMD EPYC 1st Gen
+		"m5ad":     {IdleWatts: 0.82, MaxWatts: 2.55}, // AMD EPYC 1st Gen
+		"m5d":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"m5dn":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"m5n":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"m5zn":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"m6a":      {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 3rd Gen
+		"m6g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"m6gd":     {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"m6i":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"m6id":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"m6idn":    {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"m6in":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"m7a":      {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 4th Gen
+		"m7g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton3
+		"m7gd":     {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton3
+		"m7i":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Sapphire Rapids
+		"m7i-flex": {IdleWatts: 0.64, MaxWatts: 3.97}, // Sapphire Rapids
+		"m8g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton4
+		"p2":       {IdleWatts: 0.71, MaxWatts: 3.69}, // Broadwell
+		"p3":       {IdleWatts: 0.71, MaxWatts: 3.69}, // Broadwell
+		"p3dn":     {IdleWatts: 0.65, MaxWatts: 4.26}, // Skylake
+		"p4d":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"p4de":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"p5":       {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 3rd Gen
+		"r4":       {IdleWatts: 0.71, MaxWatts: 3.69}, // Broadwell
+		"r5":       {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"r5a":      {IdleWatts: 0.82, MaxWatts: 2.55}, // AMD EPYC 1st Gen
+		"r5ad":     {IdleWatts: 0.82, MaxWatts: 2.55}, // AMD EPYC 1st Gen
+		"r5b":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"r5d":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"r5dn":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"r5n":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"r6a":      {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 3rd Gen
+		"r6g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"r6gd":     {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"r6i":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"r6id":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"r6idn":    {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"r6in":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"r7a":      {IdleWatts: 0.45, MaxWatts: 2.02}, // AMD EPYC 4th Gen
+		"r7g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton3
+		"r7gd":     {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton3
+		"r7i":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Sapphire Rapids
+		"r7iz":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Sapphire Rapids
+		"r8g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton4
+		"t2":       {IdleWatts: 1.9, MaxWatts: 6.01},  // Haswell
+		"t3":       {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"t3a":      {IdleWatts: 0.82, MaxWatts: 2.55}, // AMD EPYC 1st Gen
+		"t4g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"trn1":     {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"trn1n":    {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"u-3tb1":   {IdleWatts: 0.65, MaxWatts: 4.26}, // Skylake
+		"u-6tb1":   {IdleWatts: 0.65, MaxWatts: 4.26}, // Skylake
+		"vt1":      {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"x1":       {IdleWatts: 1.9, MaxWatts: 6.01},  // Haswell
+		"x1e":      {IdleWatts: 1.9, MaxWatts: 6.01},  // Haswell
+		"x2gd":     {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton2
+		"x2idn":    {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"x2iedn":   {IdleWatts: 0.64, MaxWatts: 3.97}, // Ice Lake
+		"x2iezn":   {IdleWatts: 0.64, MaxWatts: 3.97}, // Cascade Lake
+		"x8g":      {IdleWatts: 0.47, MaxWatts: 1.69}, // Graviton4
+		"z1d":      {IdleWatts: 0.65, MaxWatts: 4.26}, // Skylake
+
+	}
+	// AcceleratorWatts is the idle and max draw of a single GPU or accelerator device, keyed by device name
+	AcceleratorWatts = map[string]Power{
+		"a100":            {IdleWatts: 50, MaxWatts: 400},
+		"a10g":            {IdleWatts: 25, MaxWatts: 150},
+		"gaudi-hl-205":    {IdleWatts: 50, MaxWatts: 350},
+		"h100":            {IdleWatts: 60, MaxWatts: 700},
+		"inferentia":      {IdleWatts: 10, MaxWatts: 75},
+		"inferentia2":     {IdleWatts: 25, MaxWatts: 175},
+		"k80":             {IdleWatts: 25, MaxWatts: 150},
+		"l4":              {IdleWatts: 16, MaxWatts: 72},
+		"l40s":            {IdleWatts: 35, MaxWatts: 350},
+		"m60":             {IdleWatts: 25, MaxWatts: 150},
+		"radeon-pro-v520": {IdleWatts: 30, MaxWatts: 225},
+		"t4":              {IdleWatts: 10, MaxWatts: 70},
+		"t4g":             {IdleWatts: 10, MaxWatts: 70},
+		"trainium":        {IdleWatts: 30, MaxWatts: 210},
+		"v100":            {IdleWatts: 35, MaxWatts: 300},
+	}
+)
@@ -65,6 +65,7 @@ import (
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily/bootstrap"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily/bootstrap/mime"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
@@ -94,7 +95,7 @@ var _ = BeforeSuite(func() {
 
 	fakeClock = &clock.FakeClock{}
 	cloudProvider = cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, events.NewRecorder(&record.FakeRecorder{}),
-		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider)
+		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.CarbonProvider, awsEnv.RegionProvider, fakeClock)
 	cluster = state.NewCluster(fakeClock, env.Client, cloudProvider)
 	prov = provisioning.NewProvisioner(env.Client, events.NewRecorder(&record.FakeRecorder{}), cloudProvider, cluster)
 })
@@ -1018,6 +1019,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 				amiFamily,
 				nil,
+				carbon.Embodied{},
 			)
 
 			overhead := it.Overhead.Total()
@@ -1071,6 +1073,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 				amiFamily,
 				nil,
+				carbon.Embodied{},
 			)
 
 			overhead := it.Overhead.Total()
@@ -1097,6 +1100,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
 				amiFamily,
 				nil,
+				carbon.Embodied{},
 			)
 			overhead := it.Overhead.Total()
 			Expect(overhead.Memory().String()).To(Equal("1565Mi"))
@@ -1959,7 +1963,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				}})
 				nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"*": "*"}}}
 				ExpectApplied(ctx, env.Client, nodeClass)
-				controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+				controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider, awsEnv.RegionProvider, awsEnv.CarbonProvider)
 				ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 				nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
 					{
@@ -2037,7 +2041,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 					{Tags: map[string]string{"Name": "test-subnet-3"}},
 				}
 				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
-				controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+				controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider, awsEnv.RegionProvider, awsEnv.CarbonProvider)
 				ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 				pod := coretest.UnschedulablePod()
 				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
@@ -2050,7 +2054,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 					{Tags: map[string]string{"Name": "test-subnet-2"}},
 				}
 				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
-				controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+				controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider, awsEnv.RegionProvider, awsEnv.CarbonProvider)
 				ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 				pod := coretest.UnschedulablePod()
 				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
@@ -0,0 +1,173 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+)
+
+// OverridesKey is the key of the pricing overrides ConfigMap that holds the overrides
+const OverridesKey = "overrides"
+
+// Override replaces the price of the offerings it selects, for example to account for enterprise discounts, private
+// pricing or a spot bidding strategy. Offerings are selected by instance type, operating system, zone and capacity type,
+// where an empty field selects every value. Exactly one of Multiplier and Price is set.
+type Override struct {
+	InstanceType string `json:"instanceType,omitempty"`
+	// OperatingSystem is the operating system of the offerings, linux or windows. Windows prices include the Windows
+	// license, so a Price that doesn't select an operating system replaces the price of both.
+	OperatingSystem string `json:"operatingSystem,omitempty"`
+	// Zone only selects spot offerings, since on-demand prices are the same in every zone of a region
+	Zone         string `json:"zone,omitempty"`
+	CapacityType string `json:"capacityType,omitempty"`
+	// Multiplier scales the list price, such as 0.9 for a 10% discount
+	Multiplier *float64 `json:"multiplier,omitempty"`
+	// Price is the hourly price that replaces the list price
+	Price *float64 `json:"price,omitempty"`
+}
+
+func (o Override) Validate() error {
+	if o.CapacityType != "" && o.CapacityType != corev1beta1.CapacityTypeOnDemand && o.CapacityType != corev1beta1.CapacityTypeSpot {
+		return fmt.Errorf("capacityType must be one of %s or %s", corev1beta1.CapacityTypeOnDemand, corev1beta1.CapacityTypeSpot)
+	}
+	if o.OperatingSystem != "" && o.OperatingSystem != string(v1.Linux) && o.OperatingSystem != string(v1.Windows) {
+		return fmt.Errorf("operatingSystem must be one of %s or %s", v1.Linux, v1.Windows)
+	}
+	if o.Zone != "" && o.CapacityType != corev1beta1.CapacityTypeSpot {
+		return fmt.Errorf("zone requires capacityType %s, on-demand prices are the same in every zone", corev1beta1.CapacityTypeSpot)
+	}
+	if (o.Multiplier == nil) == (o.Price == nil) {
+		return fmt.Errorf("exactly one of multiplier and price is required")
+	}
+	if o.Multiplier != nil && *o.Multiplier <= 0 {
+		return fmt.Errorf("multiplier must be positive")
+	}
+	if o.Price != nil && *o.Price <= 0 {
+		return fmt.Errorf("price must be positive")
+	}
+	return nil
+}
+
+// Selects returns true if the override selects the offering
+func (o Override) Selects(instanceType, operatingSystem, zone, capacityType string) bool {
+	return (o.InstanceType == "" || o.InstanceType == instanceType) &&
+		(o.OperatingSystem == "" || o.OperatingSystem == operatingSystem) &&
+		(o.Zone == "" || o.Zone == zone) &&
+		(o.CapacityType == "" || o.CapacityType == capacityType)
+}
+
+// specificity ranks overrides so that the most specific override of an offering wins. Every combination of fields has a
+// distinct rank, and an instance type is more specific than an operating system, which is more specific than a zone,
+// which is more specific than a capacity type.
+func (o Override) specificity() int {
+	return lo.Ternary(o.InstanceType != "", 8, 0) + lo.Ternary(o.OperatingSystem != "", 4, 0) +
+		lo.Ternary(o.Zone != "", 2, 0) + lo.Ternary(o.CapacityType != "", 1, 0)
+}
+
+// Apply returns the overridden price
+func (o Override) Apply(price float64) float64 {
+	if o.Price != nil {
+		return *o.Price
+	}
+	return price * lo.FromPtr(o.Multiplier)
+}
+
+// ParseOverrides parses and validates the overrides in the data of a pricing overrides ConfigMap
+func ParseOverrides(data map[string]string) ([]Override, error) {
+	var overrides []Override
+	if err := yaml.UnmarshalStrict([]byte(data[OverridesKey]), &overrides); err != nil {
+		return nil, fmt.Errorf("parsing %s, %w", OverridesKey, err)
+	}
+	for i, o := range overrides {
+		if err := o.Validate(); err != nil {
+			return nil, fmt.Errorf("validating override %d, %w", i, err)
+		}
+		if _, ok := lo.Find(overrides[:i], func(existing Override) bool {
+			return existing.InstanceType == o.InstanceType && existing.OperatingSystem == o.OperatingSystem &&
+				existing.Zone == o.Zone && existing.CapacityType == o.CapacityType
+		}); ok {
+			return nil, fmt.Errorf("validating override %d, an earlier override selects the same offerings", i)
+		}
+	}
+	return overrides, nil
+}
+
+// Overrides holds the active price overrides. They're shared by the pricing providers of every region, and applied to
+// prices as they're looked up, so that they take effect without waiting for the next pricing update.
+type Overrides struct {
+	mu        sync.RWMutex
+	overrides []Override
+	// seqNum is a monotonically increasing change counter so that consumers can cheaply detect changes in overrides
+	seqNum uint64
+}
+
+func NewOverrides() *Overrides {
+	return &Overrides{}
+}
+
+// Set replaces the active overrides
+func (o *Overrides) Set(ctx context.Context, overrides []Override) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if reflect.DeepEqual(o.overrides, overrides) {
+		return
+	}
+	o.overrides = overrides
+	atomic.AddUint64(&o.seqNum, 1)
+	log.FromContext(ctx).WithValues("override-count", len(overrides)).V(1).Info("updated pricing overrides")
+}
+
+// List returns the active overrides
+func (o *Overrides) List() []Override {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return append([]Override{}, o.overrides...)
+}
+
+// SeqNum returns a counter that is incremented whenever the active overrides change
+func (o *Overrides) SeqNum() uint64 {
+	return atomic.LoadUint64(&o.seqNum)
+}
+
+// Apply returns the price of an offering after applying the most specific override that selects it
+func (o *Overrides) Apply(instanceType, operatingSystem, zone, capacityType string, price float64) float64 {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	selected := lo.Filter(o.overrides, func(override Override, _ int) bool {
+		return override.Selects(instanceType, operatingSystem, zone, capacityType)
+	})
+	if len(selected) == 0 {
+		return price
+	}
+	return lo.MaxBy(selected, func(a, b Override) bool { return a.specificity() > b.specificity() }).Apply(price)
+}
+
+func (o *Overrides) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.overrides = nil
+	atomic.AddUint64(&o.seqNum, 1)
+}
@@ -37,6 +37,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
 	"github.com/samber/lo"
 	"go.uber.org/multierr"
+	v1 "k8s.io/api/core/v1"
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	"sigs.k8s.io/karpenter/pkg/utils/pretty"
 )
 
@@ -46,9 +48,12 @@ type Provider interface {
 	LivenessProbe(*http.Request) error
 	InstanceTypes() []string
 	OnDemandPrice(string) (float64, bool)
+	OnDemandPriceForOS(string, string) (float64, bool)
 	SpotPrice(string, string) (float64, bool)
+	SpotPriceForOS(string, string, string) (float64, bool)
 	UpdateOnDemandPricing(context.Context) error
 	UpdateSpotPricing(context.Context) error
+	OverridesSeqNum() uint64
 }
 
 // DefaultProvider provides actual pricing data to the AWS cloud provider to allow it to make more informed decisions
@@ -65,10 +70,17 @@ type DefaultProvider struct {
 
 	muOnDemand     sync.RWMutex
 	onDemandPrices map[string]float64
+	// windowsOnDemandPrices include the Windows license, which roughly doubles the price of smaller instance types
+	windowsOnDemandPrices map[string]float64
 
 	muSpot             sync.RWMutex
 	spotPrices         map[string]zonal
 	spotPricingUpdated bool
+	// windowsSpotPrices are the zonal spot prices of Windows instances, which include the Windows license
+	windowsSpotPrices map[string]map[string]float64
+
+	// overrides are applied to the prices as they're looked up
+	overrides *Overrides
 }
 
 // zonalPricing is used to capture the per-zone price
@@ -105,12 +117,13 @@ func NewAPI(sess *session.Session, region string) pricingiface.PricingAPI {
 	return pricing.New(sess, &aws.Config{Region: aws.String(pricingAPIRegion)})
 }
 
-func NewDefaultProvider(_ context.Context, pricing pricingiface.PricingAPI, ec2Api ec2iface.EC2API, region string) *DefaultProvider {
+func NewDefaultProvider(_ context.Context, pricing pricingiface.PricingAPI, ec2Api ec2iface.EC2API, region string, overrides *Overrides) *DefaultProvider {
 	p := &DefaultProvider{
-		region:  region,
-		ec2:     ec2Api,
-		pricing: pricing,
-		cm:      pretty.NewChangeMonitor(),
+		region:    region,
+		ec2:       ec2Api,
+		pricing:   pricing,
+		cm:        pretty.NewChangeMonitor(),
+		overrides: overrides,
 	}
 	// sets the pricing data from the static default state for the provider
 	p.Reset()
@@ -127,9 +140,13 @@ func (p *DefaultProvider) InstanceTypes() []string {
 	return lo.Union(lo.Keys(p.onDemandPrices), lo.Keys(p.spotPrices))
 }
 
-// OnDemandPrice returns the last known on-demand price for a given instance type, returning an error if there is no
-// known on-demand pricing for the instance type.
+// OnDemandPrice returns the last known on-demand price for a given instance type running Linux, returning an error if
+// there is no known on-demand pricing for the instance type.
 func (p *DefaultProvider) OnDemandPrice(instanceType string) (float64, bool) {
+	return p.OnDemandPriceForOS(instanceType, string(v1.Linux))
+}
+
+func (p *DefaultProvider) onDemandPrice(instanceType string) (float64, bool) {
 	p.muOnDemand.RLock()
 	defer p.muOnDemand.RUnlock()
 	price, ok := p.onDemandPrices[instanceType]
@@ -139,9 +156,59 @@ func (p *DefaultProvider) OnDemandPrice(instanceType string) (float64, bool) {
 	return price, true
 }
 
-// SpotPrice returns the last known spot price for a given instance type and zone, returning an error
+// OnDemandPriceForOS returns the last known on-demand price for a given instance type running an operating system, as
+// the value of the kubernetes.io/os label. Windows prices fall back to the Linux price for instance types without a known
+// Windows price, such as before the first pricing update or when running in an isolated VPC.
+func (p *DefaultProvider) OnDemandPriceForOS(instanceType string, operatingSystem string) (float64, bool) {
+	price, ok := p.onDemandPriceForOS(instanceType, operatingSystem)
+	if !ok {
+		return 0.0, false
+	}
+	return p.overrides.Apply(instanceType, operatingSystem, "", corev1beta1.CapacityTypeOnDemand, price), true
+}
+
+func (p *DefaultProvider) onDemandPriceForOS(instanceType string, operatingSystem string) (float64, bool) {
+	if operatingSystem == string(v1.Windows) {
+		p.muOnDemand.RLock()
+		price, ok := p.windowsOnDemandPrices[instanceType]
+		p.muOnDemand.RUnlock()
+		if ok {
+			return price, true
+		}
+	}
+	return p.onDemandPrice(instanceType)
+}
+
+// SpotPrice returns the last known spot price for a given instance type and zone running Linux, returning an error
 // if there is no known spot pricing for that instance type or zone
 func (p *DefaultProvider) SpotPrice(instanceType string, zone string) (float64, bool) {
+	return p.SpotPriceForOS(instanceType, string(v1.Linux), zone)
+}
+
+// SpotPriceForOS returns the last known spot price for a given instance type and zone running an operating system, as the
+// value of the kubernetes.io/os label. Windows prices fall back to the Linux price for instance types and zones without a
+// known Windows spot price, such as before the first pricing update.
+func (p *DefaultProvider) SpotPriceForOS(instanceType string, operatingSystem string, zone string) (float64, bool) {
+	price, ok := p.spotPriceForOS(instanceType, operatingSystem, zone)
+	if !ok {
+		return 0.0, false
+	}
+	return p.overrides.Apply(instanceType, operatingSystem, zone, corev1beta1.CapacityTypeSpot, price), true
+}
+
+func (p *DefaultProvider) spotPriceForOS(instanceType string, operatingSystem string, zone string) (float64, bool) {
+	if operatingSystem == string(v1.Windows) {
+		p.muSpot.RLock()
+		price, ok := p.windowsSpotPrices[instanceType][zone]
+		p.muSpot.RUnlock()
+		if ok {
+			return price, true
+		}
+	}
+	return p.spotPrice(instanceType, zone)
+}
+
+func (p *DefaultProvider) spotPrice(instanceType string, zone string) (float64, bool) {
 	p.muSpot.RLock()
 	defer p.muSpot.RUnlock()
 	if val, ok := p.spotPrices[instanceType]; ok {
@@ -156,12 +223,12 @@ func (p *DefaultProvider) SpotPrice(instanceType string, zone string) (float64,
 	return 0.0, false
 }
 
-func (p *DefaultProvider) UpdateOnDemandPricing(ctx context.Context) error {
-	// standard on-demand instances
-	var wg sync.WaitGroup
-	var onDemandPrices, onDemandMetalPrices map[string]float64
-	var onDemandErr, onDemandMetalErr error
+// OverridesSeqNum returns a counter that is incremented whenever the price overrides change
+func (p *DefaultProvider) OverridesSeqNum() uint64 {
+	return p.overrides.SeqNum()
+}
 
+func (p *DefaultProvider) UpdateOnDemandPricing(ctx context.Context) error {
 	// if we are in isolated vpc, skip updating on demand pricing
 	// as pricing api may not be available
 	if options.FromContext(ctx).IsolatedVPC {
@@ -174,55 +241,107 @@ func (p *DefaultProvider) UpdateOnDemandPricing(ctx context.Context) error {
 	p.muOnDemand.Lock()
 	defer p.muOnDemand.Unlock()
 
+	// Windows instances are billed for their license, so their prices come from a separate price list
+	var wg sync.WaitGroup
+	var linuxPrices, windowsPrices map[string]float64
+	var linuxErr, windowsErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		linuxPrices, linuxErr = p.fetchOnDemandPrices(ctx, "Linux")
+	}()
+	go func() {
+		defer wg.Done()
+		windowsPrices, windowsErr = p.fetchOnDemandPrices(ctx, "Windows")
+	}()
+	wg.Wait()
+
+	if linuxErr != nil {
+		return linuxErr
+	}
+	p.onDemandPrices = linuxPrices
+	if p.cm.HasChanged("on-demand-prices", p.onDemandPrices) {
+		log.FromContext(ctx).WithValues("instance-type-count", len(p.onDemandPrices)).V(1).Info("updated on-demand pricing")
+	}
+	// the Linux prices are kept when only the Windows prices fail to update, since Windows prices fall back to them
+	if windowsErr != nil {
+		return windowsErr
+	}
+	p.windowsOnDemandPrices = windowsPrices
+	if p.cm.HasChanged("windows-on-demand-prices", p.windowsOnDemandPrices) {
+		log.FromContext(ctx).WithValues("instance-type-count", len(p.windowsOnDemandPrices)).V(1).Info("updated windows on-demand pricing")
+	}
+	return nil
+}
+
+// fetchOnDemandPrices returns the on-demand prices of the standard and bare metal instance types running an operating
+// system, as named by the pricing API
+func (p *DefaultProvider) fetchOnDemandPrices(ctx context.Context, operatingSystem string) (map[string]float64, error) {
+	var wg sync.WaitGroup
+	var onDemandPrices, onDemandMetalPrices map[string]float64
+	var onDemandErr, onDemandMetalErr error
+
+	filters := []*pricing.Filter{
+		{
+			Field: aws.String("operatingSystem"),
+			Type:  aws.String("TERM_MATCH"),
+			Value: aws.String(operatingSystem),
+		},
+	}
+	// Windows price lists also contain bring your own license prices, which don't include the license
+	if operatingSystem == "Windows" {
+		filters = append(filters, &pricing.Filter{
+			Field: aws.String("licenseModel"),
+			Type:  aws.String("TERM_MATCH"),
+			Value: aws.String("License included"),
+		})
+	}
+
+	// standard on-demand instances
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		onDemandPrices, onDemandErr = p.fetchOnDemandPricing(ctx,
-			&pricing.Filter{
+		onDemandPrices, onDemandErr = p.fetchOnDemandPricing(ctx, append([]*pricing.Filter{
+			{
 				Field: aws.String("tenancy"),
 				Type:  aws.String("TERM_MATCH"),
 				Value: aws.String("Shared"),
 			},
-			&pricing.Filter{
+			{
 				Field: aws.String("productFamily"),
 				Type:  aws.String("TERM_MATCH"),
 				Value: aws.String("Compute Instance"),
-			})
+			}}, filters...)...)
 	}()
 
 	// bare metal on-demand prices
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		onDemandMetalPrices, onDemandMetalErr = p.fetchOnDemandPricing(ctx,
-			&pricing.Filter{
+		onDemandMetalPrices, onDemandMetalErr = p.fetchOnDemandPricing(ctx, append([]*pricing.Filter{
+			{
 				Field: aws.String("tenancy"),
 				Type:  aws.String("TERM_MATCH"),
 				Value: aws.String("Dedicated"),
 			},
-			&pricing.Filter{
+			{
 				Field: aws.String("productFamily"),
 				Type:  aws.String("TERM_MATCH"),
 				Value: aws.String("Compute Instance (bare metal)"),
-			})
+			}}, filters...)...)
 	}()
 
 	wg.Wait()
 
 	err := multierr.Append(onDemandErr, onDemandMetalErr)
 	if err != nil {
-		return fmt.Errorf("retreiving on-demand pricing data, %w", err)
+		return nil, fmt.Errorf("retreiving %s on-demand pricing data, %w", operatingSystem, err)
 	}
 
 	if len(onDemandPrices) == 0 || len(onDemandMetalPrices) == 0 {
-		return fmt.Errorf("no on-demand pricing found")
-	}
-
-	p.onDemandPrices = lo.Assign(onDemandPrices, onDemandMetalPrices)
-	if p.cm.HasChanged("on-demand-prices", p.onDemandPrices) {
-		log.FromContext(ctx).WithValues("instance-type-count", len(p.onDemandPrices)).V(1).Info("updated on-demand pricing")
+		return nil, fmt.Errorf("no %s on-demand pricing found", operatingSystem)
 	}
-	return nil
+	return lo.Assign(onDemandPrices, onDemandMetalPrices), nil
 }
 
 func (p *DefaultProvider) fetchOnDemandPricing(ctx context.Context, additionalFilters ...*pricing.Filter) (map[string]float64, error) {
@@ -243,11 +362,6 @@ func (p *DefaultProvider) fetchOnDemandPricing(ctx context.Context, additionalFi
 			Type:  aws.String("TERM_MATCH"),
 			Value: aws.String("NA"),
 		},
-		{
-			Field: aws.String("operatingSystem"),
-			Type:  aws.String("TERM_MATCH"),
-			Value: aws.String("Linux"),
-		},
 		{
 			Field: aws.String("capacitystatus"),
 			Type:  aws.String("TERM_MATCH"),
@@ -275,7 +389,9 @@ func (p *DefaultProvider) fetchOnDemandPricing(ctx context.Context, additionalFi
 	return prices, nil
 }
 
-func (p *DefaultProvider) spotPage(ctx context.Context, prices map[string]map[string]float64) func(output *ec2.DescribeSpotPriceHistoryOutput, b bool) bool {
+// spotPage records the spot prices of a page of spot price history, keeping the prices of Windows instances apart since
+// they include the Windows license
+func (p *DefaultProvider) spotPage(ctx context.Context, prices, windowsPrices map[string]map[string]float64) func(output *ec2.DescribeSpotPriceHistoryOutput, b bool) bool {
 	return func(output *ec2.DescribeSpotPriceHistoryOutput, b bool) bool {
 		for _, sph := range output.SpotPriceHistory {
 			spotPriceStr := aws.StringValue(sph.SpotPrice)
@@ -290,11 +406,15 @@ func (p *DefaultProvider) spotPage(ctx context.Context, prices map[string]map[st
 			}
 			instanceType := aws.StringValue(sph.InstanceType)
 			az := aws.StringValue(sph.AvailabilityZone)
-			_, ok := prices[instanceType]
+			osPrices := prices
+			if strings.HasPrefix(aws.StringValue(sph.ProductDescription), "Windows") {
+				osPrices = windowsPrices
+			}
+			_, ok := osPrices[instanceType]
 			if !ok {
-				prices[instanceType] = map[string]float64{}
+				osPrices[instanceType] = map[string]float64{}
 			}
-			prices[instanceType][az] = spotPrice
+			osPrices[instanceType][az] = spotPrice
 		}
 		return true
 	}
@@ -356,6 +476,7 @@ func (p *DefaultProvider) onDemandPage(ctx context.Context, prices map[string]fl
 // nolint: gocyclo
 func (p *DefaultProvider) UpdateSpotPricing(ctx context.Context) error {
 	prices := map[string]map[string]float64{}
+	windowsPrices := map[string]map[string]float64{}
 
 	p.muSpot.Lock()
 	defer p.muSpot.Unlock()
@@ -365,11 +486,13 @@ func (p *DefaultProvider) UpdateSpotPricing(ctx context.Context) error {
 			ProductDescriptions: []*string{
 				aws.String("Linux/UNIX"),
 				aws.String("Linux/UNIX (Amazon VPC)"),
+				aws.String("Windows"),
+				aws.String("Windows (Amazon VPC)"),
 			},
 			// get the latest spot price for each instance type
 			StartTime: aws.Time(time.Now()),
 		},
-		p.spotPage(ctx, prices),
+		p.spotPage(ctx, prices, windowsPrices),
 	)
 
 	if err != nil {
@@ -389,6 +512,14 @@ func (p *DefaultProvider) UpdateSpotPricing(ctx context.Context) error {
 		}
 		totalOfferings += len(zoneData)
 	}
+	for it, zoneData := range windowsPrices {
+		if _, ok := p.windowsSpotPrices[it]; !ok {
+			p.windowsSpotPrices[it] = map[string]float64{}
+		}
+		for zone, price := range zoneData {
+			p.windowsSpotPrices[it][zone] = price
+		}
+	}
 
 	p.spotPricingUpdated = true
 	if p.cm.HasChanged("spot-prices", p.spotPrices) {
@@ -396,6 +527,9 @@ func (p *DefaultProvider) UpdateSpotPricing(ctx context.Context) error {
 			"instance-type-count", len(p.onDemandPrices),
 			"offering-count", totalOfferings).V(1).Info("updated spot pricing with instance types and offerings")
 	}
+	if p.cm.HasChanged("windows-spot-prices", p.windowsSpotPrices) {
+		log.FromContext(ctx).WithValues("instance-type-count", len(p.windowsSpotPrices)).V(1).Info("updated windows spot pricing")
+	}
 	return nil
 }
 
@@ -426,7 +560,11 @@ func (p *DefaultProvider) Reset() {
 	}
 
 	p.onDemandPrices = staticPricing
+	// there's no static Windows pricing, so Windows prices fall back to the Linux prices until a price update
+	p.windowsOnDemandPrices = map[string]float64{}
 	// default our spot pricing to the same as the on-demand pricing until a price update
 	p.spotPrices = populateInitialSpotPricing(staticPricing)
 	p.spotPricingUpdated = false
+	// there's no static Windows pricing, so Windows spot prices fall back to the Linux prices until a price update
+	p.windowsSpotPrices = map[string]map[string]float64{}
 }
@@ -0,0 +1,215 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package region
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/samber/lo"
+	"go.uber.org/multierr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
+	"github.com/aws/karpenter-provider-aws/pkg/utils"
+)
+
+// Providers are the providers used to discover offerings and launch instances in a single region
+type Providers struct {
+	Region                 string
+	SubnetProvider         subnet.Provider
+	SecurityGroupProvider  securitygroup.Provider
+	AMIProvider            amifamily.Provider
+	LaunchTemplateProvider launchtemplate.Provider
+	PricingProvider        pricing.Provider
+	InstanceTypeProvider   instancetype.Provider
+	InstanceProvider       instance.Provider
+}
+
+// Factory creates the providers for a region
+type Factory func(string) *Providers
+
+type Provider interface {
+	Region() string
+	Get(context.Context, string) (*Providers, error)
+	ForZone(context.Context, string) (*Providers, error)
+	List() []*Providers
+	Update(context.Context) error
+}
+
+// DefaultProvider holds the providers of the cluster's region alongside the providers of the additional regions that
+// EC2NodeClasses launch into. The providers for an additional region are created the first time the region is used,
+// and are populated with the region's instance types, offerings and pricing before they're returned.
+type DefaultProvider struct {
+	home    *Providers
+	factory Factory
+
+	mu      sync.Mutex
+	regions map[string]*Providers
+	// initializing serializes the creation of each additional region's providers, so that a region that's slow to
+	// initialize doesn't block the regions that are already in use
+	initializing map[string]*sync.Mutex
+}
+
+func NewDefaultProvider(home *Providers, factory Factory) *DefaultProvider {
+	return &DefaultProvider{
+		home:         home,
+		factory:      factory,
+		regions:      map[string]*Providers{},
+		initializing: map[string]*sync.Mutex{},
+	}
+}
+
+// Region returns the cluster's region
+func (p *DefaultProvider) Region() string {
+	return p.home.Region
+}
+
+// Get returns the providers for a region, creating the providers of an additional region if it hasn't been used before
+func (p *DefaultProvider) Get(ctx context.Context, region string) (*Providers, error) {
+	if region == p.home.Region {
+		return p.home, nil
+	}
+	p.mu.Lock()
+	providers, ok := p.regions[region]
+	if !ok {
+		if _, ok := p.initializing[region]; !ok {
+			p.initializing[region] = &sync.Mutex{}
+		}
+	}
+	initializing := p.initializing[region]
+	p.mu.Unlock()
+	if ok {
+		return providers, nil
+	}
+
+	initializing.Lock()
+	defer initializing.Unlock()
+	// The region may have been initialized while waiting for another caller to initialize it
+	p.mu.Lock()
+	providers, ok = p.regions[region]
+	p.mu.Unlock()
+	if ok {
+		return providers, nil
+	}
+	providers = p.factory(region)
+	if err := update(ctx, providers); err != nil {
+		return nil, fmt.Errorf("initializing region %s, %w", region, err)
+	}
+	log.FromContext(ctx).WithValues("region", region).V(1).Info("initialized additional region")
+	p.mu.Lock()
+	p.regions[region] = providers
+	p.mu.Unlock()
+	return providers, nil
+}
+
+// ForZone returns the providers for the region of a zone. Zones that aren't named after a region are assumed to be in
+// the cluster's region.
+func (p *DefaultProvider) ForZone(ctx context.Context, zone string) (*Providers, error) {
+	region, ok := utils.RegionForZone(zone)
+	if !ok {
+		return p.home, nil
+	}
+	return p.Get(ctx, region)
+}
+
+// List returns the providers for the cluster's region followed by those of every additional region that's been used
+func (p *DefaultProvider) List() []*Providers {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	regions := lo.Values(p.regions)
+	sort.Slice(regions, func(i, j int) bool { return regions[i].Region < regions[j].Region })
+	return append([]*Providers{p.home}, regions...)
+}
+
+// Update refreshes the instance types, offerings and pricing of the additional regions. The providers of the cluster's
+// region are refreshed by their own controllers.
+func (p *DefaultProvider) Update(ctx context.Context) error {
+	var errs error
+	for _, providers := range p.List()[1:] {
+		if err := update(ctx, providers); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("updating region %s, %w", providers.Region, err))
+		}
+	}
+	return errs
+}
+
+// Reset drops the providers of the additional regions, so that they're recreated when next used
+func (p *DefaultProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.regions = map[string]*Providers{}
+	p.initializing = map[string]*sync.Mutex{}
+}
+
+func update(ctx context.Context, providers *Providers) error {
+	// Pricing falls back to the static pricing of the region, so only failing to discover instance types and their
+	// offerings stops the region from being used
+	if err := multierr.Combine(
+		providers.PricingProvider.UpdateOnDemandPricing(ctx),
+		providers.PricingProvider.UpdateSpotPricing(ctx),
+	); err != nil {
+		log.FromContext(ctx).WithValues("region", providers.Region).Error(err, "failed updating pricing")
+	}
+	return multierr.Combine(
+		providers.InstanceTypeProvider.UpdateInstanceTypes(ctx),
+		providers.InstanceTypeProvider.UpdateInstanceTypeOfferings(ctx),
+	)
+}
+
+// NodeClass returns a copy of an EC2NodeClass that selects and has resolved the subnets, security groups and AMIs of
+// one of its additional regions, so that it can be passed to the providers of that region as is. It returns false if
+// the region isn't one of the EC2NodeClass's additional regions or hasn't been resolved yet.
+func NodeClass(nodeClass *v1beta1.EC2NodeClass, region string) (*v1beta1.EC2NodeClass, bool) {
+	spec, ok := lo.Find(nodeClass.Spec.AdditionalRegions, func(r v1beta1.AdditionalRegion) bool { return r.Name == region })
+	if !ok {
+		return nil, false
+	}
+	status, ok := lo.Find(nodeClass.Status.AdditionalRegions, func(r v1beta1.AdditionalRegionStatus) bool { return r.Name == region })
+	if !ok || len(status.Subnets) == 0 || len(status.SecurityGroups) == 0 || len(status.AMIs) == 0 {
+		return nil, false
+	}
+	regional := SelectorNodeClass(nodeClass, spec)
+	regional.Status.Subnets = status.Subnets
+	regional.Status.SecurityGroups = status.SecurityGroups
+	regional.Status.AMIs = status.AMIs
+	return regional, true
+}
+
+// SelectorNodeClass returns a copy of an EC2NodeClass that selects the subnets, security groups and AMIs of one of its
+// additional regions, without any of them resolved
+func SelectorNodeClass(nodeClass *v1beta1.EC2NodeClass, region v1beta1.AdditionalRegion) *v1beta1.EC2NodeClass {
+	regional := nodeClass.DeepCopy()
+	regional.Spec.SubnetSelectorTerms = region.SubnetSelectorTerms
+	regional.Spec.SecurityGroupSelectorTerms = region.SecurityGroupSelectorTerms
+	if len(region.AMISelectorTerms) != 0 {
+		regional.Spec.AMISelectorTerms = region.AMISelectorTerms
+	}
+	regional.Spec.AdditionalRegions = nil
+	regional.Status.Subnets = nil
+	regional.Status.SecurityGroups = nil
+	regional.Status.AMIs = nil
+	regional.Status.AdditionalRegions = nil
+	return regional
+}
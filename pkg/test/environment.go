@@ -21,6 +21,7 @@ import (
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	"sigs.k8s.io/karpenter/pkg/operator/scheme"
@@ -29,11 +30,14 @@ import (
 	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
 	"github.com/aws/karpenter-provider-aws/pkg/fake"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/commitment"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/region"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/version"
@@ -50,11 +54,14 @@ func init() {
 
 type Environment struct {
 	// API
-	EC2API     *fake.EC2API
-	EKSAPI     *fake.EKSAPI
-	SSMAPI     *fake.SSMAPI
-	IAMAPI     *fake.IAMAPI
-	PricingAPI *fake.PricingAPI
+	EC2API             *fake.EC2API
+	EKSAPI             *fake.EKSAPI
+	SSMAPI             *fake.SSMAPI
+	IAMAPI             *fake.IAMAPI
+	PricingAPI         *fake.PricingAPI
+	CarbonIntensityAPI *fake.CarbonIntensityAPI
+	// RegionalEC2API serves the EC2 API of every additional region
+	RegionalEC2API *fake.EC2API
 
 	// Cache
 	EC2Cache                      *cache.Cache
@@ -75,15 +82,20 @@ type Environment struct {
 	SecurityGroupProvider   *securitygroup.DefaultProvider
 	InstanceProfileProvider *instanceprofile.DefaultProvider
 	PricingProvider         *pricing.DefaultProvider
+	PricingOverrides        *pricing.Overrides
+	CarbonProvider          *fake.CarbonProvider
+	CommitmentProvider      *commitment.DefaultProvider
 	AMIProvider             *amifamily.DefaultProvider
 	AMIResolver             *amifamily.Resolver
 	VersionProvider         *version.DefaultProvider
 	LaunchTemplateProvider  *launchtemplate.DefaultProvider
+	RegionProvider          *region.DefaultProvider
 }
 
 func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment {
 	// API
 	ec2api := fake.NewEC2API()
+	regionalEC2API := fake.NewEC2API()
 	eksapi := fake.NewEKSAPI()
 	ssmapi := fake.NewSSMAPI()
 	iamapi := fake.NewIAMAPI()
@@ -100,16 +112,20 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 	securityGroupCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
 	instanceProfileCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
 	fakePricingAPI := &fake.PricingAPI{}
+	fakeCarbonIntensityAPI := fake.NewCarbonIntensityAPI()
 
 	// Providers
-	pricingProvider := pricing.NewDefaultProvider(ctx, fakePricingAPI, ec2api, fake.DefaultRegion)
+	pricingOverrides := pricing.NewOverrides()
+	pricingProvider := pricing.NewDefaultProvider(ctx, fakePricingAPI, ec2api, fake.DefaultRegion, pricingOverrides)
+	carbonProvider := fake.NewCarbonProvider(carbon.NewDefaultProvider(ctx, clock.RealClock{}, fake.DefaultRegion))
+	commitmentProvider := commitment.NewDefaultProvider()
 	subnetProvider := subnet.NewDefaultProvider(ec2api, subnetCache, availableIPAdressCache, associatePublicIPAddressCache)
 	securityGroupProvider := securitygroup.NewDefaultProvider(ec2api, securityGroupCache)
 	versionProvider := version.NewDefaultProvider(env.KubernetesInterface, kubernetesVersionCache)
 	instanceProfileProvider := instanceprofile.NewDefaultProvider(fake.DefaultRegion, iamapi, instanceProfileCache)
 	amiProvider := amifamily.NewDefaultProvider(versionProvider, ssmapi, ec2api, ec2Cache)
 	amiResolver := amifamily.NewResolver(amiProvider)
-	instanceTypesProvider := instancetype.NewDefaultProvider(fake.DefaultRegion, instanceTypeCache, ec2api, subnetProvider, unavailableOfferingsCache, pricingProvider)
+	instanceTypesProvider := instancetype.NewDefaultProvider(fake.DefaultRegion, instanceTypeCache, ec2api, subnetProvider, unavailableOfferingsCache, pricingProvider, carbonProvider, commitmentProvider)
 	launchTemplateProvider :=
 		launchtemplate.NewDefaultProvider(
 			ctx,
@@ -133,13 +149,57 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 			subnetProvider,
 			launchTemplateProvider,
 		)
+	regionProvider := region.NewDefaultProvider(&region.Providers{
+		Region:                 fake.DefaultRegion,
+		SubnetProvider:         subnetProvider,
+		SecurityGroupProvider:  securityGroupProvider,
+		AMIProvider:            amiProvider,
+		LaunchTemplateProvider: launchTemplateProvider,
+		PricingProvider:        pricingProvider,
+		InstanceTypeProvider:   instanceTypesProvider,
+		InstanceProvider:       instanceProvider,
+	}, func(name string) *region.Providers {
+		regionalSubnetProvider := subnet.NewDefaultProvider(regionalEC2API, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval),
+			cache.New(awscache.AvailableIPAddressTTL, awscache.DefaultCleanupInterval), cache.New(awscache.AssociatePublicIPAddressTTL, awscache.DefaultCleanupInterval))
+		regionalSecurityGroupProvider := securitygroup.NewDefaultProvider(regionalEC2API, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+		regionalPricingProvider := pricing.NewDefaultProvider(ctx, fakePricingAPI, regionalEC2API, name, pricingOverrides)
+		regionalAMIProvider := amifamily.NewDefaultProvider(versionProvider, ssmapi, regionalEC2API, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+		regionalLaunchTemplateProvider := launchtemplate.NewDefaultProvider(
+			ctx,
+			cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval),
+			regionalEC2API,
+			eksapi,
+			amifamily.NewResolver(regionalAMIProvider),
+			regionalSecurityGroupProvider,
+			regionalSubnetProvider,
+			lo.ToPtr("ca-bundle"),
+			make(chan struct{}),
+			net.ParseIP("10.0.100.10"),
+			"https://test-cluster",
+		)
+		regionalInstanceTypesProvider := instancetype.NewDefaultProvider(name, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval), regionalEC2API,
+			regionalSubnetProvider, unavailableOfferingsCache, regionalPricingProvider, carbonProvider, commitmentProvider)
+		return &region.Providers{
+			Region:                 name,
+			SubnetProvider:         regionalSubnetProvider,
+			SecurityGroupProvider:  regionalSecurityGroupProvider,
+			AMIProvider:            regionalAMIProvider,
+			LaunchTemplateProvider: regionalLaunchTemplateProvider,
+			PricingProvider:        regionalPricingProvider,
+			InstanceTypeProvider:   regionalInstanceTypesProvider,
+			InstanceProvider: instance.NewDefaultProvider(ctx, name, regionalEC2API, unavailableOfferingsCache, regionalInstanceTypesProvider,
+				regionalSubnetProvider, regionalLaunchTemplateProvider),
+		}
+	})
 
 	return &Environment{
-		EC2API:     ec2api,
-		EKSAPI:     eksapi,
-		SSMAPI:     ssmapi,
-		IAMAPI:     iamapi,
-		PricingAPI: fakePricingAPI,
+		EC2API:             ec2api,
+		EKSAPI:             eksapi,
+		SSMAPI:             ssmapi,
+		IAMAPI:             iamapi,
+		PricingAPI:         fakePricingAPI,
+		CarbonIntensityAPI: fakeCarbonIntensityAPI,
+		RegionalEC2API:     regionalEC2API,
 
 		EC2Cache:                      ec2Cache,
 		KubernetesVersionCache:        kubernetesVersionCache,
@@ -158,20 +218,30 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 		LaunchTemplateProvider:  launchTemplateProvider,
 		InstanceProfileProvider: instanceProfileProvider,
 		PricingProvider:         pricingProvider,
+		PricingOverrides:        pricingOverrides,
+		CarbonProvider:          carbonProvider,
+		CommitmentProvider:      commitmentProvider,
 		AMIProvider:             amiProvider,
 		AMIResolver:             amiResolver,
 		VersionProvider:         versionProvider,
+		RegionProvider:          regionProvider,
 	}
 }
 
 func (env *Environment) Reset() {
 	env.EC2API.Reset()
+	env.RegionalEC2API.Reset()
 	env.EKSAPI.Reset()
 	env.SSMAPI.Reset()
 	env.IAMAPI.Reset()
 	env.PricingAPI.Reset()
+	env.CarbonIntensityAPI.Reset()
 	env.PricingProvider.Reset()
+	env.PricingOverrides.Reset()
+	env.CarbonProvider.Reset()
+	env.CommitmentProvider.Reset()
 	env.InstanceTypesProvider.Reset()
+	env.RegionProvider.Reset()
 
 	env.EC2Cache.Flush()
 	env.KubernetesVersionCache.Flush()
@@ -34,6 +34,14 @@ type OptionsFields struct {
 	VMMemoryOverheadPercent *float64
 	InterruptionQueue       *string
 	ReservedENIs            *int
+
+	CarbonIntensityURL         *string
+	CarbonIntensityAuthHeader  *string
+	CarbonIntensityZoneMapping *string
+	CarbonIntensityTTL         *time.Duration
+
+	PricingCommitmentsFile    *string
+	PricingOverridesConfigMap *string
 }
 
 func Options(overrides ...OptionsFields) *options.Options {
@@ -53,5 +61,13 @@ func Options(overrides ...OptionsFields) *options.Options {
 		VMMemoryOverheadPercent: lo.FromPtrOr(opts.VMMemoryOverheadPercent, 0.075),
 		InterruptionQueue:       lo.FromPtrOr(opts.InterruptionQueue, ""),
 		ReservedENIs:            lo.FromPtrOr(opts.ReservedENIs, 0),
+
+		CarbonIntensityURL:         lo.FromPtrOr(opts.CarbonIntensityURL, ""),
+		CarbonIntensityAuthHeader:  lo.FromPtrOr(opts.CarbonIntensityAuthHeader, ""),
+		CarbonIntensityZoneMapping: lo.FromPtrOr(opts.CarbonIntensityZoneMapping, ""),
+		CarbonIntensityTTL:         lo.FromPtrOr(opts.CarbonIntensityTTL, 2*time.Hour),
+
+		PricingCommitmentsFile:    lo.FromPtrOr(opts.PricingCommitmentsFile, ""),
+		PricingOverridesConfigMap: lo.FromPtrOr(opts.PricingOverridesConfigMap, ""),
 	}
 }
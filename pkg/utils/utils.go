@@ -26,6 +26,8 @@ import (
 
 var (
 	instanceIDRegex = regexp.MustCompile(`aws:///(?P<AZ>.*)/(?P<InstanceID>.*)`)
+	// zoneRegionRegex matches the region that prefixes the names of availability zones, local zones and wavelength zones
+	zoneRegionRegex = regexp.MustCompile(`^[a-z]{2}(-gov)?-[a-z]+-[0-9]+`)
 )
 
 // ParseInstanceID parses the provider ID stored on the node to get the instance ID
@@ -43,6 +45,22 @@ func ParseInstanceID(providerID string) (string, error) {
 	return "", fmt.Errorf("parsing instance id %s", providerID)
 }
 
+// ParseZone parses the provider ID stored on the node to get the zone of the instance
+// associated with a node
+func ParseZone(providerID string) (string, error) {
+	matches := instanceIDRegex.FindStringSubmatch(providerID)
+	if matches == nil {
+		return "", fmt.Errorf("parsing zone %s", providerID)
+	}
+	return matches[instanceIDRegex.SubexpIndex("AZ")], nil
+}
+
+// RegionForZone returns the region that a zone is in, which prefixes the name of every zone
+func RegionForZone(zone string) (string, bool) {
+	region := zoneRegionRegex.FindString(zone)
+	return region, region != ""
+}
+
 // MergeTags takes a variadic list of maps and merges them together into a list of
 // EC2 tags to be passed into EC2 API calls
 func MergeTags(tags ...map[string]string) []*ec2.Tag {
@@ -0,0 +1,239 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/operator/scheme"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
+)
+
+var region string
+var period time.Duration
+var output string
+var outFile string
+var intensityFile string
+
+func init() {
+	flag.StringVar(&region, "region", os.Getenv("AWS_REGION"), "region of the cluster, defaults to the region label of its nodes")
+	flag.DurationVar(&period, "period", 30*24*time.Hour, "period, ending now, to report the emissions of running nodes over")
+	flag.StringVar(&output, "output", "csv", "output format, one of csv or json")
+	flag.StringVar(&outFile, "out-file", "", "file to output the generated data, defaults to stdout")
+	flag.StringVar(&intensityFile, "intensity-file", "", "JSON file mapping regions and zones to grid intensities in gCO2e/kWh, defaults to the intensity data built into Karpenter")
+	flag.Parse()
+}
+
+// NodeEmissions is the estimated emissions of a node over the reporting period
+type NodeEmissions struct {
+	NodeClaim    string  `json:"nodeClaim"`
+	Node         string  `json:"node,omitempty"`
+	NodePool     string  `json:"nodePool"`
+	Zone         string  `json:"zone"`
+	InstanceType string  `json:"instanceType"`
+	CapacityType string  `json:"capacityType"`
+	Hours        float64 `json:"hours"`
+	Intensity    float64 `json:"intensity"`
+	GCO2e        float64 `json:"gco2e"`
+}
+
+// GroupEmissions is the estimated emissions of a group of nodes, such as a NodePool or zone, over the reporting period
+type GroupEmissions struct {
+	Name  string  `json:"name"`
+	Nodes int     `json:"nodes"`
+	Hours float64 `json:"hours"`
+	GCO2e float64 `json:"gco2e"`
+}
+
+type Report struct {
+	Start     time.Time        `json:"start"`
+	End       time.Time        `json:"end"`
+	Region    string           `json:"region"`
+	Nodes     []NodeEmissions  `json:"nodes"`
+	NodePools []GroupEmissions `json:"nodePools"`
+	Zones     []GroupEmissions `json:"zones"`
+}
+
+func main() {
+	if output != "csv" && output != "json" {
+		log.Fatalf("output must be one of csv or json")
+	}
+	if period <= 0 {
+		log.Fatalf("period must be positive")
+	}
+	lo.Must0(apis.AddToScheme(scheme.Scheme))
+	kubeClient := lo.Must(client.New(config.GetConfigOrDie(), client.Options{Scheme: scheme.Scheme}))
+	ctx := context.Background()
+
+	nodeClaimList := &corev1beta1.NodeClaimList{}
+	lo.Must0(kubeClient.List(ctx, nodeClaimList))
+	nodeList := &v1.NodeList{}
+	lo.Must0(kubeClient.List(ctx, nodeList))
+	nodeClassList := &v1beta1.EC2NodeClassList{}
+	lo.Must0(kubeClient.List(ctx, nodeClassList))
+
+	if region == "" {
+		node, ok := lo.Find(nodeList.Items, func(n v1.Node) bool { return n.Labels[v1.LabelTopologyRegion] != "" })
+		if !ok {
+			log.Fatalf("region cannot be empty")
+		}
+		region = node.Labels[v1.LabelTopologyRegion]
+	}
+	intensities := map[string]float64{}
+	if intensityFile != "" {
+		lo.Must0(json.Unmarshal(lo.Must(os.ReadFile(intensityFile)), &intensities))
+	}
+	carbonProvider := carbon.NewDefaultProvider(ctx, clock.RealClock{}, region)
+	intensity := func(zone string) (float64, bool) {
+		if intensity, ok := intensities[zone]; ok {
+			return intensity, true
+		}
+		if intensity, ok := intensities[region]; ok {
+			return intensity, true
+		}
+		return carbonProvider.ZoneIntensity(zone)
+	}
+
+	end := time.Now().UTC()
+	report := Report{Start: end.Add(-period), End: end, Region: region}
+	nodes := lo.SliceToMap(nodeList.Items, func(n v1.Node) (string, v1.Node) { return n.Spec.ProviderID, n })
+	includeEmbodied := lo.SliceToMap(nodeClassList.Items, func(nc v1beta1.EC2NodeClass) (string, bool) {
+		return nc.Name, nc.Spec.CarbonPolicy != nil && lo.FromPtr(nc.Spec.CarbonPolicy.IncludeEmbodied)
+	})
+	for _, nodeClaim := range nodeClaimList.Items {
+		if nodeClaim.Status.ProviderID == "" {
+			continue
+		}
+		node := nodes[nodeClaim.Status.ProviderID]
+		// NodeClaims are labeled with their instance type at launch, but nodes also carry any labels added since
+		labels := lo.Assign(node.Labels, nodeClaim.Labels)
+		info, ok := instancetype.InstanceTypeInfoFromLabels(labels)
+		if !ok {
+			log.Printf("skipping nodeclaim %s, its labels don't describe its instance type", nodeClaim.Name)
+			continue
+		}
+		zone := labels[v1.LabelTopologyZone]
+		gridIntensity, ok := intensity(zone)
+		if !ok {
+			log.Printf("skipping nodeclaim %s, there's no intensity data for zone %q", nodeClaim.Name, zone)
+			continue
+		}
+		gramsPerHour := instancetype.OperationalGramsPerHour(instancetype.ComputePower(info), region, gridIntensity)
+		if nodeClaim.Spec.NodeClassRef != nil && includeEmbodied[nodeClaim.Spec.NodeClassRef.Name] {
+			// The embodied emissions label is rounded to the nearest kilogram
+			if kg, err := strconv.ParseFloat(labels[v1beta1.LabelInstanceEmbodiedKgCO2e], 64); err == nil {
+				gramsPerHour += kg * 1000 / carbon.HostLifespanHours
+			}
+		}
+		hours := end.Sub(launchedAt(&nodeClaim, report.Start)).Hours()
+		report.Nodes = append(report.Nodes, NodeEmissions{
+			NodeClaim:    nodeClaim.Name,
+			Node:         nodeClaim.Status.NodeName,
+			NodePool:     labels[corev1beta1.NodePoolLabelKey],
+			Zone:         zone,
+			InstanceType: labels[v1.LabelInstanceTypeStable],
+			CapacityType: labels[corev1beta1.CapacityTypeLabelKey],
+			Hours:        hours,
+			Intensity:    gridIntensity,
+			GCO2e:        gramsPerHour * hours,
+		})
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].NodeClaim < report.Nodes[j].NodeClaim })
+	report.NodePools = group(report.Nodes, func(n NodeEmissions) string { return n.NodePool })
+	report.Zones = group(report.Nodes, func(n NodeEmissions) string { return n.Zone })
+
+	var w io.Writer = os.Stdout
+	if outFile != "" {
+		file := lo.Must(os.Create(outFile))
+		defer file.Close()
+		w = file
+	}
+	if output == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		lo.Must0(encoder.Encode(report))
+		return
+	}
+	writeCSV(w, report)
+}
+
+// launchedAt returns when a NodeClaim launched, or the start of the reporting period if it launched before it
+func launchedAt(nodeClaim *corev1beta1.NodeClaim, start time.Time) time.Time {
+	launched := nodeClaim.CreationTimestamp.Time
+	if condition := nodeClaim.StatusConditions().Get(corev1beta1.ConditionTypeLaunched); condition.IsTrue() {
+		launched = condition.LastTransitionTime.Time
+	}
+	if launched.Before(start) {
+		return start
+	}
+	return launched
+}
+
+func group(nodes []NodeEmissions, key func(NodeEmissions) string) []GroupEmissions {
+	groups := lo.MapToSlice(lo.GroupBy(nodes, key), func(name string, nodes []NodeEmissions) GroupEmissions {
+		return GroupEmissions{
+			Name:  name,
+			Nodes: len(nodes),
+			Hours: lo.SumBy(nodes, func(n NodeEmissions) float64 { return n.Hours }),
+			GCO2e: lo.SumBy(nodes, func(n NodeEmissions) float64 { return n.GCO2e }),
+		}
+	})
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// writeCSV writes one row per node followed by the totals of each NodePool and zone, distinguished by the scope column
+func writeCSV(out io.Writer, report Report) {
+	w := csv.NewWriter(out)
+	defer w.Flush()
+
+	lo.Must0(w.Write([]string{"Scope", "Name", "Node", "NodePool", "Zone", "Instance Type", "Capacity Type", "Nodes", "Node Hours", "Intensity (gCO2e/kWh)", "Emissions (gCO2e)"}))
+	for _, n := range report.Nodes {
+		lo.Must0(w.Write([]string{"nodeclaim", n.NodeClaim, n.Node, n.NodePool, n.Zone, n.InstanceType, n.CapacityType, "1",
+			formatFloat(n.Hours), formatFloat(n.Intensity), formatFloat(n.GCO2e)}))
+	}
+	for _, g := range report.NodePools {
+		lo.Must0(w.Write([]string{"nodepool", g.Name, "", g.Name, "", "", "", fmt.Sprint(g.Nodes), formatFloat(g.Hours), "", formatFloat(g.GCO2e)}))
+	}
+	for _, g := range report.Zones {
+		lo.Must0(w.Write([]string{"zone", g.Name, "", "", g.Name, "", "", fmt.Sprint(g.Nodes), formatFloat(g.Hours), "", formatFloat(g.GCO2e)}))
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', 2, 64)
+}
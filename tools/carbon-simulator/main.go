@@ -0,0 +1,121 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+)
+
+var snapshotFile string
+var output string
+var outFile string
+
+func init() {
+	flag.StringVar(&snapshotFile, "snapshot", "", "YAML or JSON file with the snapshot of instance types, offerings, prices, intensities, NodePool, EC2NodeClass, pending pods and candidate carbon policies to simulate")
+	flag.StringVar(&output, "output", "csv", "output format, one of csv or json")
+	flag.StringVar(&outFile, "out-file", "", "file to output the generated data, defaults to stdout")
+	flag.Parse()
+}
+
+func main() {
+	if output != "csv" && output != "json" {
+		log.Fatalf("output must be one of csv or json")
+	}
+	if snapshotFile == "" {
+		log.Fatalf("snapshot cannot be empty")
+	}
+	snapshot := &Snapshot{}
+	lo.Must0(yaml.Unmarshal(lo.Must(os.ReadFile(snapshotFile)), snapshot))
+	if snapshot.Region == "" {
+		log.Fatalf("snapshot region cannot be empty")
+	}
+	if snapshot.NodeClass.Name == "" {
+		snapshot.NodeClass.Name = "simulated"
+	}
+	if snapshot.NodePool.Name == "" {
+		snapshot.NodePool.Name = "simulated"
+	}
+	// The snapshot's own carbon policy is simulated first, so that candidates can be compared against it
+	candidates := append([]Candidate{{Name: "current", CarbonPolicy: snapshot.NodeClass.Spec.CarbonPolicy}}, snapshot.Candidates...)
+
+	ctx := options.ToContext(context.Background(), &options.Options{ClusterName: "simulated", VMMemoryOverheadPercent: 0.075})
+	var results []Result
+	for _, candidate := range candidates {
+		simulator, err := NewSimulator(ctx, snapshot)
+		if err != nil {
+			log.Fatalf("creating simulator, %s", err)
+		}
+		result, err := simulator.Simulate(ctx, candidate)
+		if err != nil {
+			log.Fatalf("simulating candidate %s, %s", candidate.Name, err)
+		}
+		results = append(results, result)
+	}
+
+	var w io.Writer = os.Stdout
+	if outFile != "" {
+		file := lo.Must(os.Create(outFile))
+		defer file.Close()
+		w = file
+	}
+	if output == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		lo.Must0(encoder.Encode(results))
+		return
+	}
+	writeCSV(w, results)
+}
+
+// writeCSV writes the totals of each candidate followed by one row per node that it's expected to launch, distinguished
+// by the scope column
+func writeCSV(out io.Writer, results []Result) {
+	w := csv.NewWriter(out)
+	defer w.Flush()
+
+	lo.Must0(w.Write([]string{"Scope", "Candidate", "Instance Type", "Zone", "Capacity Type", "Nodes", "Pods", "Unschedulable Pods",
+		"Price ($/hour)", "Intensity (gCO2e/kWh)", "Emissions (gCO2e/hour)"}))
+	for _, r := range results {
+		pods := lo.SumBy(r.Nodes, func(n Node) int { return len(n.Pods) })
+		lo.Must0(w.Write([]string{"candidate", r.Candidate, "", "", "", fmt.Sprint(len(r.Nodes)), fmt.Sprint(pods), fmt.Sprint(len(r.UnschedulablePods)),
+			formatFloat(r.PricePerHour, 4), "", formatFloat(r.GCO2ePerHour, 2)}))
+	}
+	for _, r := range results {
+		for _, n := range r.Nodes {
+			lo.Must0(w.Write([]string{"node", r.Candidate, n.InstanceType, n.Zone, n.CapacityType, "1", strings.Join(n.Pods, " "), "",
+				formatFloat(n.Price, 4), formatFloat(n.Intensity, 2), formatFloat(n.GCO2ePerHour, 2)}))
+		}
+	}
+}
+
+func formatFloat(f float64, precision int) string {
+	scale := math.Pow(10, float64(precision))
+	return strconv.FormatFloat(math.Round(f*scale)/scale, 'f', precision, 64)
+}
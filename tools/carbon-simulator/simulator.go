@@ -0,0 +1,378 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	pricingapi "github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/clock"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/carbon"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/commitment"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
+)
+
+// Snapshot is a recorded view of the instance types, offerings, prices and grid intensities of a region, along with a
+// NodePool, the EC2NodeClass it references and a set of pending pods
+type Snapshot struct {
+	Region string `json:"region"`
+	// InstanceTypes and Offerings are in the format of the EC2 DescribeInstanceTypes and DescribeInstanceTypeOfferings
+	// responses, with availability zones as the offering locations
+	InstanceTypes []*ec2.InstanceTypeInfo     `json:"instanceTypes"`
+	Offerings     []*ec2.InstanceTypeOffering `json:"offerings"`
+	// OnDemandPrices maps instance types to their hourly on-demand price, defaulting to the prices built into Karpenter
+	OnDemandPrices map[string]float64 `json:"onDemandPrices,omitempty"`
+	// SpotPrices maps zones to instance types to their hourly spot price
+	SpotPrices map[string]map[string]float64 `json:"spotPrices,omitempty"`
+	// Intensities maps zones to their grid intensity in gCO2e/kWh, defaulting to the intensity data built into Karpenter
+	Intensities map[string]float64 `json:"intensities,omitempty"`
+	// Subnets are the subnets that the EC2NodeClass resolves to
+	Subnets   []v1beta1.Subnet     `json:"subnets"`
+	NodePool  corev1beta1.NodePool `json:"nodePool"`
+	NodeClass v1beta1.EC2NodeClass `json:"nodeClass"`
+	Pods      []v1.Pod             `json:"pods"`
+	// Candidates are the carbon policies to simulate in place of the EC2NodeClass's own policy
+	Candidates []Candidate `json:"candidates,omitempty"`
+}
+
+// Candidate is a carbon policy to simulate the EC2NodeClass with
+type Candidate struct {
+	Name         string                `json:"name"`
+	CarbonPolicy *v1beta1.CarbonPolicy `json:"carbonPolicy,omitempty"`
+}
+
+// Node is a node that the simulation expects to launch
+type Node struct {
+	InstanceType string   `json:"instanceType"`
+	Zone         string   `json:"zone"`
+	CapacityType string   `json:"capacityType"`
+	Pods         []string `json:"pods"`
+	Price        float64  `json:"price"`
+	Intensity    float64  `json:"intensity"`
+	GCO2ePerHour float64  `json:"gco2ePerHour"`
+}
+
+// Result is the expected outcome of provisioning the pending pods with a candidate carbon policy
+type Result struct {
+	Candidate         string   `json:"candidate"`
+	Nodes             []Node   `json:"nodes"`
+	UnschedulablePods []string `json:"unschedulablePods,omitempty"`
+	PricePerHour      float64  `json:"pricePerHour"`
+	GCO2ePerHour      float64  `json:"gco2ePerHour"`
+}
+
+// simulatedNodeClaim is a NodeClaim that pods are packed onto, along with the instance types that can still hold them
+type simulatedNodeClaim struct {
+	requirements  scheduling.Requirements
+	requests      v1.ResourceList
+	pods          []*v1.Pod
+	instanceTypes []*cloudprovider.InstanceType
+}
+
+// Simulator replays instance type resolution and launches against fake EC2, pricing and carbon APIs that serve a
+// snapshot. Each Simulator has its own fakes and caches, so results don't depend on what was simulated before.
+type Simulator struct {
+	snapshot *Snapshot
+
+	ec2api                *fake.EC2API
+	pricingProvider       *pricing.DefaultProvider
+	carbonProvider        *fake.CarbonProvider
+	instanceTypesProvider *instancetype.DefaultProvider
+	instanceProvider      *instance.DefaultProvider
+}
+
+func NewSimulator(ctx context.Context, snapshot *Snapshot) (*Simulator, error) {
+	ec2api := fake.NewEC2API()
+	pricingAPI := &fake.PricingAPI{}
+	ec2api.DescribeInstanceTypesOutput.Set(&ec2.DescribeInstanceTypesOutput{InstanceTypes: snapshot.InstanceTypes})
+	ec2api.DescribeInstanceTypeOfferingsOutput.Set(&ec2.DescribeInstanceTypeOfferingsOutput{InstanceTypeOfferings: snapshot.Offerings})
+	ec2api.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: lo.Map(snapshot.Subnets, func(s v1beta1.Subnet, _ int) *ec2.Subnet {
+		return &ec2.Subnet{SubnetId: aws.String(s.ID), AvailabilityZone: aws.String(s.Zone), AvailabilityZoneId: aws.String(s.ZoneID), AvailableIpAddressCount: aws.Int64(math.MaxInt32)}
+	})})
+	if len(snapshot.OnDemandPrices) > 0 {
+		pricingAPI.GetProductsOutput.Set(&pricingapi.GetProductsOutput{
+			PriceList: lo.MapToSlice(snapshot.OnDemandPrices, func(instanceType string, price float64) aws.JSONValue {
+				return fake.NewOnDemandPrice(instanceType, price)
+			}),
+		})
+	}
+	if len(snapshot.SpotPrices) > 0 {
+		var history []*ec2.SpotPrice
+		for zone, prices := range snapshot.SpotPrices {
+			for instanceType, price := range prices {
+				history = append(history, &ec2.SpotPrice{
+					AvailabilityZone: aws.String(zone),
+					InstanceType:     aws.String(instanceType),
+					SpotPrice:        aws.String(strconv.FormatFloat(price, 'f', -1, 64)),
+					Timestamp:        aws.Time(time.Now()),
+				})
+			}
+		}
+		ec2api.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{SpotPriceHistory: history})
+	}
+
+	unavailableOfferings := awscache.NewUnavailableOfferings()
+	pricingProvider := pricing.NewDefaultProvider(ctx, pricingAPI, ec2api, snapshot.Region, pricing.NewOverrides())
+	carbonProvider := fake.NewCarbonProvider(carbon.NewDefaultProvider(ctx, clock.RealClock{}, snapshot.Region))
+	for zone, intensity := range snapshot.Intensities {
+		carbonProvider.SetZoneIntensity(zone, intensity)
+	}
+	subnetProvider := subnet.NewDefaultProvider(ec2api, newCache(), cache.New(awscache.AvailableIPAddressTTL, awscache.DefaultCleanupInterval),
+		cache.New(awscache.AssociatePublicIPAddressTTL, awscache.DefaultCleanupInterval))
+	securityGroupProvider := securitygroup.NewDefaultProvider(ec2api, newCache())
+	amiProvider := amifamily.NewDefaultProvider(nil, fake.NewSSMAPI(), ec2api, newCache())
+	instanceTypesProvider := instancetype.NewDefaultProvider(snapshot.Region, newCache(), ec2api, subnetProvider, unavailableOfferings, pricingProvider, carbonProvider,
+		commitment.NewDefaultProvider())
+	launchTemplateProvider := launchtemplate.NewDefaultProvider(ctx, newCache(), ec2api, fake.NewEKSAPI(), amifamily.NewResolver(amiProvider),
+		securityGroupProvider, subnetProvider, lo.ToPtr("simulated"), make(chan struct{}), net.ParseIP("10.100.0.10"), "https://simulated")
+	instanceProvider := instance.NewDefaultProvider(ctx, snapshot.Region, ec2api, unavailableOfferings, instanceTypesProvider, subnetProvider, launchTemplateProvider)
+
+	if len(snapshot.OnDemandPrices) > 0 {
+		if err := pricingProvider.UpdateOnDemandPricing(ctx); err != nil {
+			return nil, fmt.Errorf("updating on-demand pricing, %w", err)
+		}
+	}
+	if len(snapshot.SpotPrices) > 0 {
+		if err := pricingProvider.UpdateSpotPricing(ctx); err != nil {
+			return nil, fmt.Errorf("updating spot pricing, %w", err)
+		}
+	}
+	if err := instanceTypesProvider.UpdateInstanceTypes(ctx); err != nil {
+		return nil, fmt.Errorf("updating instance types, %w", err)
+	}
+	if err := instanceTypesProvider.UpdateInstanceTypeOfferings(ctx); err != nil {
+		return nil, fmt.Errorf("updating instance type offerings, %w", err)
+	}
+	if err := launchTemplateProvider.ResolveClusterCIDR(ctx); err != nil {
+		return nil, fmt.Errorf("resolving cluster CIDR, %w", err)
+	}
+	return &Simulator{
+		snapshot:              snapshot,
+		ec2api:                ec2api,
+		pricingProvider:       pricingProvider,
+		carbonProvider:        carbonProvider,
+		instanceTypesProvider: instanceTypesProvider,
+		instanceProvider:      instanceProvider,
+	}, nil
+}
+
+func newCache() *cache.Cache {
+	return cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
+}
+
+// Simulate packs the pending pods onto NodeClaims like the provisioner would and launches each NodeClaim with the
+// EC2NodeClass, reporting the instance type, zone and capacity type that EC2 is expected to choose from the request
+func (s *Simulator) Simulate(ctx context.Context, candidate Candidate) (Result, error) {
+	nodeClass := s.nodeClass(candidate)
+	nodePool := &s.snapshot.NodePool
+	instanceTypes, err := s.instanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+	if err != nil {
+		return Result{}, fmt.Errorf("listing instance types, %w", err)
+	}
+	result := Result{Candidate: candidate.Name}
+	nodeClaims, unschedulable := s.pack(instanceTypes)
+	result.UnschedulablePods = unschedulable
+	for _, nc := range nodeClaims {
+		nodeClaim := &corev1beta1.NodeClaim{}
+		nodeClaim.Labels = lo.Assign(nodePool.Spec.Template.Labels, map[string]string{corev1beta1.NodePoolLabelKey: nodePool.Name})
+		nodeClaim.Spec.Requirements = nc.requirements.NodeSelectorRequirements()
+		nodeClaim.Spec.NodeClassRef = &corev1beta1.NodeClassReference{Name: nodeClass.Name}
+		if _, err := s.instanceProvider.Create(ctx, nodeClass, nodeClaim, nc.instanceTypes); err != nil {
+			s.ec2api.CreateFleetBehavior.Reset()
+			result.UnschedulablePods = append(result.UnschedulablePods, podNames(nc.pods)...)
+			continue
+		}
+		node, err := s.fulfill(nodeClass, s.ec2api.CreateFleetBehavior.CalledWithInput.Pop(), instanceTypes)
+		if err != nil {
+			return Result{}, err
+		}
+		node.Pods = podNames(nc.pods)
+		result.Nodes = append(result.Nodes, node)
+		result.PricePerHour += node.Price
+		result.GCO2ePerHour += node.GCO2ePerHour
+	}
+	sort.Strings(result.UnschedulablePods)
+	return result, nil
+}
+
+// nodeClass returns the snapshot's EC2NodeClass with the candidate's carbon policy and the status that its
+// controllers would resolve, so that launches don't depend on AMI, security group or instance profile discovery
+func (s *Simulator) nodeClass(candidate Candidate) *v1beta1.EC2NodeClass {
+	nodeClass := s.snapshot.NodeClass.DeepCopy()
+	nodeClass.Spec.CarbonPolicy = candidate.CarbonPolicy
+	nodeClass.Status.Subnets = s.snapshot.Subnets
+	nodeClass.Status.SecurityGroups = []v1beta1.SecurityGroup{{ID: "sg-simulated"}}
+	nodeClass.Status.InstanceProfile = "simulated"
+	nodeClass.Status.AMIs = lo.Map([]string{corev1beta1.ArchitectureAmd64, corev1beta1.ArchitectureArm64}, func(arch string, _ int) v1beta1.AMI {
+		return v1beta1.AMI{ID: "ami-simulated-" + arch, Requirements: []v1.NodeSelectorRequirement{
+			{Key: v1.LabelArchStable, Operator: v1.NodeSelectorOpIn, Values: []string{arch}},
+		}}
+	})
+	return nodeClass
+}
+
+// pack places the pods, largest first, onto the first NodeClaim that an instance type can still hold them on, opening
+// a new NodeClaim when none can. Pods that no instance type can hold, or that don't tolerate the NodePool's taints, are
+// returned as unschedulable.
+func (s *Simulator) pack(instanceTypes []*cloudprovider.InstanceType) ([]*simulatedNodeClaim, []string) {
+	nodePool := &s.snapshot.NodePool
+	pods := lo.Map(s.snapshot.Pods, func(p v1.Pod, _ int) *v1.Pod { return p.DeepCopy() })
+	sort.SliceStable(pods, func(i, j int) bool {
+		iRequests, jRequests := resources.RequestsForPods(pods[i]), resources.RequestsForPods(pods[j])
+		if c := iRequests.Cpu().Cmp(*jRequests.Cpu()); c != 0 {
+			return c > 0
+		}
+		if c := iRequests.Memory().Cmp(*jRequests.Memory()); c != 0 {
+			return c > 0
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	nodePoolRequirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodePool.Spec.Template.Spec.Requirements...)
+	nodePoolRequirements.Add(scheduling.NewLabelRequirements(nodePool.Spec.Template.Labels).Values()...)
+	nodePoolRequirements.Add(scheduling.NewRequirement(corev1beta1.NodePoolLabelKey, v1.NodeSelectorOpIn, nodePool.Name))
+
+	var nodeClaims []*simulatedNodeClaim
+	var unschedulable []string
+	for _, pod := range pods {
+		if err := scheduling.Taints(nodePool.Spec.Template.Spec.Taints).Tolerates(pod); err != nil {
+			unschedulable = append(unschedulable, pod.Name)
+			continue
+		}
+		scheduled := false
+		for _, nc := range nodeClaims {
+			if added, ok := add(nc.requirements, nc.requests, nc.instanceTypes, pod); ok {
+				added.pods = append(nc.pods, pod)
+				*nc = *added
+				scheduled = true
+				break
+			}
+		}
+		if scheduled {
+			continue
+		}
+		if added, ok := add(nodePoolRequirements, v1.ResourceList{}, instanceTypes, pod); ok {
+			added.pods = []*v1.Pod{pod}
+			nodeClaims = append(nodeClaims, added)
+			continue
+		}
+		unschedulable = append(unschedulable, pod.Name)
+	}
+	return nodeClaims, unschedulable
+}
+
+// add returns the NodeClaim that results from adding a pod to a NodeClaim with the requirements, requests and
+// instance types, and false if the pod isn't compatible with the requirements or no instance type can hold it
+func add(requirements scheduling.Requirements, requests v1.ResourceList, instanceTypes []*cloudprovider.InstanceType, pod *v1.Pod) (*simulatedNodeClaim, bool) {
+	podRequirements := scheduling.NewPodRequirements(pod)
+	if err := requirements.Compatible(podRequirements, scheduling.AllowUndefinedWellKnownLabels); err != nil {
+		return nil, false
+	}
+	combined := scheduling.NewRequirements(requirements.Values()...)
+	combined.Add(podRequirements.Values()...)
+	total := resources.Merge(requests, resources.RequestsForPods(pod), v1.ResourceList{v1.ResourcePods: resource.MustParse("1")})
+	remaining := lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool {
+		return it.Requirements.Compatible(combined, scheduling.AllowUndefinedWellKnownLabels) == nil &&
+			len(it.Offerings.Available().Compatible(combined)) > 0 &&
+			resources.Fits(total, it.Allocatable())
+	})
+	if len(remaining) == 0 {
+		return nil, false
+	}
+	return &simulatedNodeClaim{requirements: combined, requests: total, instanceTypes: remaining}, true
+}
+
+// fulfill chooses the override that EC2 is expected to launch from a CreateFleet request. Prioritized requests launch
+// the override with the lowest priority. Otherwise, the cheapest override is launched, which for spot ignores the
+// capacity pool depth that the price capacity optimized strategy also weighs.
+func (s *Simulator) fulfill(nodeClass *v1beta1.EC2NodeClass, input *ec2.CreateFleetInput, instanceTypes []*cloudprovider.InstanceType) (Node, error) {
+	capacityType := aws.StringValue(input.TargetCapacitySpecification.DefaultTargetCapacityType)
+	overrides := lo.FlatMap(input.LaunchTemplateConfigs, func(ltc *ec2.FleetLaunchTemplateConfigRequest, _ int) []*ec2.FleetLaunchTemplateOverridesRequest {
+		return ltc.Overrides
+	})
+	if len(overrides) == 0 {
+		return Node{}, fmt.Errorf("launching with no overrides")
+	}
+	price := func(o *ec2.FleetLaunchTemplateOverridesRequest) float64 {
+		if o.Priority != nil {
+			return aws.Float64Value(o.Priority)
+		}
+		return s.price(aws.StringValue(o.InstanceType), aws.StringValue(o.AvailabilityZone), capacityType)
+	}
+	override := lo.MinBy(overrides, func(a, b *ec2.FleetLaunchTemplateOverridesRequest) bool { return price(a) < price(b) })
+	node := Node{
+		InstanceType: aws.StringValue(override.InstanceType),
+		Zone:         aws.StringValue(override.AvailabilityZone),
+		CapacityType: capacityType,
+	}
+	node.Price = s.price(node.InstanceType, node.Zone, capacityType)
+	info, ok := lo.Find(s.snapshot.InstanceTypes, func(i *ec2.InstanceTypeInfo) bool { return aws.StringValue(i.InstanceType) == node.InstanceType })
+	if !ok {
+		return Node{}, fmt.Errorf("launched instance type %s isn't in the snapshot", node.InstanceType)
+	}
+	if intensity, ok := s.carbonProvider.ZoneIntensity(node.Zone); ok {
+		node.Intensity = intensity
+		node.GCO2ePerHour = instancetype.OperationalGramsPerHour(instancetype.ComputePower(info), s.snapshot.Region, intensity)
+	}
+	if nodeClass.Spec.CarbonPolicy != nil && lo.FromPtr(nodeClass.Spec.CarbonPolicy.IncludeEmbodied) {
+		if it, ok := lo.Find(instanceTypes, func(it *cloudprovider.InstanceType) bool { return it.Name == node.InstanceType }); ok {
+			// The embodied emissions label is rounded to the nearest kilogram
+			if kg, err := strconv.ParseFloat(it.Requirements.Get(v1beta1.LabelInstanceEmbodiedKgCO2e).Any(), 64); err == nil {
+				node.GCO2ePerHour += kg * 1000 / carbon.HostLifespanHours
+			}
+		}
+	}
+	return node, nil
+}
+
+// price returns the hourly price of an instance type, without the cost of carbon that offerings are weighted by
+func (s *Simulator) price(instanceType, zone, capacityType string) float64 {
+	if capacityType == corev1beta1.CapacityTypeSpot {
+		price, _ := s.pricingProvider.SpotPrice(instanceType, zone)
+		return price
+	}
+	price, _ := s.pricingProvider.OnDemandPrice(instanceType)
+	return price
+}
+
+func podNames(pods []*v1.Pod) []string {
+	return lo.Map(pods, func(p *v1.Pod, _ int) string { return p.Name })
+}